@@ -12,8 +12,10 @@ var (
 	jiraUrl     string
 	projectName string
 	sprintName  string
+	filterID    string
 	outputFile  string
 	configFile  string
+	verbose     bool
 )
 
 func init() {
@@ -26,6 +28,8 @@ func init() {
 	fl.StringVar(&jiraUrl, "jira.url", "", "URL to JIRA worskspace, overwrite config.JiraUrl")
 	fl.StringVar(&projectName, "project", "", "The project to grab issues from, overwrite config.Filters.Project")
 	fl.StringVar(&sprintName, "sprint", "", "Name of the sprint to export, overwrite config.Filters.Sprint")
+	fl.BoolVarP(&verbose, "verbose", "v", false, "Log the raw jira responses, overwrite config.Verbose")
+	fl.StringVar(&filterID, "filter", "", "ID of a saved filter whose JQL is used instead of config.Filters, overwrite config.FilterId")
 }
 
 var exportCmd = &cobra.Command{
@@ -54,16 +58,36 @@ var exportCmd = &cobra.Command{
 			c.Filters["Sprint"] = sprintName
 		}
 
+		if filterID != "" {
+			c.FilterID = filterID
+		}
+
+		if verbose {
+			c.Verbose = true
+		}
+
+		if c.UserAgent == "" {
+			c.UserAgent = "ferry/" + Version
+		}
+
 		// start Jira Finder instance
 		err, f := jirafinder.NewJiraFinder(c)
 		if err != nil {
 			return err
 		}
 
+		f.Progress = func(fetched, total int) {
+			fmt.Printf(" Fetched %d/%d issues\n", fetched, total)
+		}
+
 		if err := f.Search(); err != nil {
 			return err
 		}
 
+		if len(f.Errors) > 0 {
+			fmt.Printf(" %d issues could not be processed and are not exported\n", len(f.Errors))
+		}
+
 		fmt.Println(" Download complete!!. Results exported to " + "'" + f.Config.DownloadPath + "'")
 		return nil
 	},
@@ -4,24 +4,74 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"github.com/pelletier/go-toml"
 	"github.com/pkg/errors"
-	"io/ioutil"
+	"gopkg.in/yaml.v2"
+	"io"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Configuration struct {
-	JiraURL          string                 `json:"JiraUrl"`
-	Credentials      Credentials            `json:"Credentials"`
-	Filters          map[string]interface{} `json:"Filters"`
-	FieldsToRetrieve []string               `json:"FieldsToRetrieve"`
-	DownloadPath     string                 `json:"DownloadPath"`
-	AuthToken        string
+	JiraURL                 string                 `json:"JiraUrl" yaml:"JiraUrl" toml:"JiraUrl"`
+	Deployment              string                 `json:"Deployment" yaml:"Deployment" toml:"Deployment"`
+	APIPath                 string                 `json:"ApiPath" yaml:"ApiPath" toml:"ApiPath"`
+	UserAgent               string                 `json:"UserAgent" yaml:"UserAgent" toml:"UserAgent"`
+	Credentials             Credentials            `json:"Credentials" yaml:"Credentials" toml:"Credentials"`
+	Filters                 map[string]interface{} `json:"Filters" yaml:"Filters" toml:"Filters"`
+	FieldsToRetrieve        []string               `json:"FieldsToRetrieve" yaml:"FieldsToRetrieve" toml:"FieldsToRetrieve"`
+	FieldAliases            map[string]string      `json:"FieldAliases" yaml:"FieldAliases" toml:"FieldAliases"`
+	FieldFallbacks          map[string][]string    `json:"FieldFallbacks" yaml:"FieldFallbacks" toml:"FieldFallbacks"`
+	FieldsByKeys            bool                   `json:"FieldsByKeys" yaml:"FieldsByKeys" toml:"FieldsByKeys"`
+	FilterID                string                 `json:"FilterId" yaml:"FilterId" toml:"FilterId"`
+	DownloadPath            string                 `json:"DownloadPath" yaml:"DownloadPath" toml:"DownloadPath"`
+	AnonymizeFields         []string               `json:"AnonymizeFields" yaml:"AnonymizeFields" toml:"AnonymizeFields"`
+	MaxFieldLength          map[string]int         `json:"MaxFieldLength" yaml:"MaxFieldLength" toml:"MaxFieldLength"`
+	EmptyValue              *string                `json:"EmptyValue" yaml:"EmptyValue" toml:"EmptyValue"`
+	NormalizeWhitespace     bool                   `json:"NormalizeWhitespace" yaml:"NormalizeWhitespace" toml:"NormalizeWhitespace"`
+	StripCommas             bool                   `json:"StripCommas" yaml:"StripCommas" toml:"StripCommas"`
+	RawColumns              bool                   `json:"RawColumns" yaml:"RawColumns" toml:"RawColumns"`
+	PruneEmptyColumns       bool                   `json:"PruneEmptyColumns" yaml:"PruneEmptyColumns" toml:"PruneEmptyColumns"`
+	IssueKeyPattern         string                 `json:"IssueKeyPattern" yaml:"IssueKeyPattern" toml:"IssueKeyPattern"`
+	MaxJqlLength            int                    `json:"MaxJqlLength" yaml:"MaxJqlLength" toml:"MaxJqlLength"`
+	SearchParams            map[string]string      `json:"SearchParams" yaml:"SearchParams" toml:"SearchParams"`
+	Expand                  []string               `json:"Expand" yaml:"Expand" toml:"Expand"`
+	MinimalSubTasks         bool                   `json:"MinimalSubTasks" yaml:"MinimalSubTasks" toml:"MinimalSubTasks"`
+	IncludeRemoteLinks      bool                   `json:"IncludeRemoteLinks" yaml:"IncludeRemoteLinks" toml:"IncludeRemoteLinks"`
+	IncludeTransitions      bool                   `json:"IncludeTransitions" yaml:"IncludeTransitions" toml:"IncludeTransitions"`
+	IncludeComponentDetails bool                   `json:"IncludeComponentDetails" yaml:"IncludeComponentDetails" toml:"IncludeComponentDetails"`
+	DeveloperField          string                 `json:"DeveloperField" yaml:"DeveloperField" toml:"DeveloperField"`
+	SprintField             string                 `json:"SprintField" yaml:"SprintField" toml:"SprintField"`
+	StoryPointsField        string                 `json:"StoryPointsField" yaml:"StoryPointsField" toml:"StoryPointsField"`
+	FlaggedField            string                 `json:"FlaggedField" yaml:"FlaggedField" toml:"FlaggedField"`
+	TimeTrackingField       string                 `json:"TimeTrackingField" yaml:"TimeTrackingField" toml:"TimeTrackingField"`
+	MaxResponseBytes        int64                  `json:"MaxResponseBytes" yaml:"MaxResponseBytes" toml:"MaxResponseBytes"`
+	Deadline                string                 `json:"Deadline" yaml:"Deadline" toml:"Deadline"`
+	CacheDir                string                 `json:"CacheDir" yaml:"CacheDir" toml:"CacheDir"`
+	CacheTTL                string                 `json:"CacheTTL" yaml:"CacheTTL" toml:"CacheTTL"`
+	Workers                 int                    `json:"Workers" yaml:"Workers" toml:"Workers"`
+	ContinueOnError         bool                   `json:"ContinueOnError" yaml:"ContinueOnError" toml:"ContinueOnError"`
+	TokenPagination         bool                   `json:"TokenPagination" yaml:"TokenPagination" toml:"TokenPagination"`
+	Verbose                 bool                   `json:"Verbose" yaml:"Verbose" toml:"Verbose"`
+	DoneStatuses            []string               `json:"DoneStatuses" yaml:"DoneStatuses" toml:"DoneStatuses"`
+	StatusPhaseMap          map[string]string      `json:"StatusPhaseMap" yaml:"StatusPhaseMap" toml:"StatusPhaseMap"`
+	TimeZone                string                 `json:"TimeZone" yaml:"TimeZone" toml:"TimeZone"`
+	// AuthToken is the base64 encoded "username:token" of the basic authentication, given pre-encoded or built from the Credentials
+	AuthToken string `json:"AuthToken" yaml:"AuthToken" toml:"AuthToken"`
 }
 
 type Credentials struct {
-	Username string
-	Password string
+	Username string `yaml:"Username" toml:"Username"`
+	Password string `yaml:"Password" toml:"Password"`
+	// APIToken replaces the password on JIRA Cloud, used instead of the Password when set
+	APIToken string `json:"APIToken" yaml:"APIToken" toml:"APIToken"`
+}
+
+// BrowseURL gives the url of the issue page, the JiraURL may have a context path and a trailing slash
+func (c Configuration) BrowseURL(key string) string {
+	return strings.TrimRight(c.JiraURL, "/") + "/browse/" + url.PathEscape(key)
 }
 
 func ensureFile(confgFile string) (error, string) {
@@ -52,30 +102,67 @@ func encodeStringToBase64(val string) string {
 }
 
 func New(confgFile string) (error, *Configuration) {
-	var c *Configuration
-
-	err, confgFile := ensureFile(confgFile)
+	err, c := LoadConfig(confgFile)
 	if err != nil {
 		return err, nil
 	}
 
 	fmt.Println(" Fetching data based on the configuration file => " + "'" + confgFile + "'")
 
-	jsonFile, err := os.Open(confgFile)
+	return nil, c
+}
+
+// LoadConfig reads the config file, the format is detected by its extension (.json, .yaml, .yml or .toml)
+func LoadConfig(path string) (error, *Configuration) {
+	var c Configuration
+
+	err, path := ensureFile(path)
+	if err != nil {
+		return err, nil
+	}
+
+	var unmarshal func([]byte, interface{}) error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		unmarshal = json.Unmarshal
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	case ".toml":
+		unmarshal = toml.Unmarshal
+	default:
+		return errors.Errorf("unsupported config format '%s', expected .json, .yaml, .yml or .toml", ext), nil
+	}
+
+	configFile, err := os.Open(path)
 	if err != nil {
 		return errors.Wrapf(err, "failed to open config file"), nil
 	}
 
-	defer jsonFile.Close()
+	defer configFile.Close()
 
-	byteValue, _ := ioutil.ReadAll(jsonFile)
+	byteValue, _ := io.ReadAll(configFile)
 
-	err = json.Unmarshal([]byte(byteValue), &c)
+	err = unmarshal(byteValue, &c)
 	if err != nil {
 		return errors.Wrapf(err, "failed to parse config file"), nil
 	}
 
-	c.AuthToken = encodeStringToBase64(c.Credentials.Username + ":" + c.Credentials.Password)
+	c.AuthToken = c.authToken()
 
-	return nil, c
+	return nil, &c
+}
+
+// authToken gives the configured AuthToken when already encoded without Credentials, else encodes
+// the username with the api token or the password
+func (c Configuration) authToken() string {
+	if c.AuthToken != "" && c.Credentials.Username == "" {
+		return c.AuthToken
+	}
+
+	secret := c.Credentials.APIToken
+	if secret == "" {
+		secret = c.Credentials.Password
+	}
+
+	return encodeStringToBase64(c.Credentials.Username + ":" + secret)
 }
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"encoding/base64"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -28,4 +31,63 @@ func TestJiraFinder_CreateConfigSuccess(t *testing.T) {
 	r.NoErrorf(err, "expected reading config succeed, got error: '%s'", err)
 	r.NotNil(c, "expected to have an healthy config, got nil")
 	r.NotEmpty(c.AuthToken, ".AuthToken should not be empty")
-}
\ No newline at end of file
+}
+
+func TestJiraFinder_LoadConfigFormats(t *testing.T) {
+	r := assert.New(t)
+
+	err, expected := LoadConfig("../example_config/sample_config_bug_search.json")
+	r.NoErrorf(err, "expected reading json config succeed, got error: '%s'", err)
+
+	for _, file := range []string{"sample_config_bug_search.yaml", "sample_config_bug_search.toml"} {
+		err, c := LoadConfig("../example_config/" + file)
+		r.NoErrorf(err, "expected reading %s succeed, got error: '%s'", file, err)
+		r.EqualValues(expected, c, "config from %s differs from json config", file)
+	}
+}
+
+func TestJiraFinder_LoadConfigUnknownFormat(t *testing.T) {
+	r := assert.New(t)
+
+	err, _ := LoadConfig("../README.md")
+	r.Errorf(err, "expected LoadConfig to fail")
+	r.Containsf(err.Error(), "unsupported config format", "expected 'unsupported config format', got '%s'", err)
+}
+
+func TestConfiguration_BrowseURL(t *testing.T) {
+	r := assert.New(t)
+
+	expected := map[string]string{
+		"https://your-domain.atlassian.net":   "https://your-domain.atlassian.net/browse/POS-7",
+		"https://your-domain.atlassian.net/":  "https://your-domain.atlassian.net/browse/POS-7",
+		"https://jira.example.com/jira":       "https://jira.example.com/jira/browse/POS-7",
+		"https://jira.example.com/jira/":      "https://jira.example.com/jira/browse/POS-7",
+		"http://localhost:8080/context/jira/": "http://localhost:8080/context/jira/browse/POS-7",
+	}
+
+	for jiraURL, want := range expected {
+		r.Equalf(want, Configuration{JiraURL: jiraURL}.BrowseURL("POS-7"), "wrong browse url for %s", jiraURL)
+	}
+}
+
+func TestJiraFinder_LoadConfigAuthToken(t *testing.T) {
+	r := assert.New(t)
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("jane@example.com:api-token"))
+	configs := map[string]string{
+		"pre-encoded":    `{"JiraUrl": "https://your-domain.atlassian.net", "AuthToken": "` + encoded + `"}`,
+		"api token":      `{"JiraUrl": "https://your-domain.atlassian.net", "Credentials": {"Username": "jane@example.com", "APIToken": "api-token"}}`,
+		"password":       `{"JiraUrl": "https://your-domain.atlassian.net", "Credentials": {"Username": "jane@example.com", "Password": "api-token"}}`,
+		"token priority": `{"JiraUrl": "https://your-domain.atlassian.net", "Credentials": {"Username": "jane@example.com", "Password": "old", "APIToken": "api-token"}}`,
+	}
+
+	dir := t.TempDir()
+	for name, content := range configs {
+		path := filepath.Join(dir, "config.json")
+		r.NoError(os.WriteFile(path, []byte(content), 0644))
+
+		err, c := LoadConfig(path)
+		r.NoErrorf(err, "expected reading %s config succeed, got error: '%s'", name, err)
+		r.Equalf(encoded, c.AuthToken, "wrong auth token of %s config", name)
+	}
+}
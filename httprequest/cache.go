@@ -0,0 +1,84 @@
+package httprequest
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// CacheTransport caches the successful GET responses in a directory, the repeated requests being served from the disk,
+// e.g. to run the enrichment again offline. The cached responses are recorded like the ReplayTransport ones,
+// the directory can be replayed too
+type CacheTransport struct {
+	Dir string
+	// TTL is the age above which a cached response is requested again, the cache never expires when 0
+	TTL time.Duration
+	// Transport sends the requests which are not cached, http.DefaultTransport when nil
+	Transport http.RoundTripper
+}
+
+// NewCacheTransport gives a transport caching the responses in the directory for the ttl
+func NewCacheTransport(dir string, ttl time.Duration) *CacheTransport {
+	return &CacheTransport{Dir: dir, TTL: ttl}
+}
+
+// RoundTrip serves the cached response of the request, or sends it and caches its response
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if req.Method != http.MethodGet {
+		return transport.RoundTrip(req)
+	}
+
+	key := requestKey(req)
+	if f, ok := t.cached(key); ok {
+		return f.response(req), nil
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response to cache for %s", key)
+	}
+
+	if err := writeFixture(t.Dir, fixture{key, resp.StatusCode, string(body)}); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// cached gives the cached response of the request key, false when missing, expired or unreadable
+func (t *CacheTransport) cached(key string) (fixture, bool) {
+	path := fixturePath(t.Dir, key)
+
+	info, err := os.Stat(path)
+	if err != nil || (t.TTL > 0 && time.Since(info.ModTime()) > t.TTL) {
+		return fixture{}, false
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fixture{}, false
+	}
+
+	var f fixture
+	if err := json.Unmarshal(content, &f); err != nil || f.Request != key {
+		return fixture{}, false
+	}
+
+	return f, true
+}
@@ -0,0 +1,101 @@
+package httprequest
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheTransport_SecondRunFromDisk(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	calls := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		fmt.Fprintf(w, `{"id": "10006", "key": "POS-7", "call": %d}`, calls)
+	}))
+
+	c := NewClient(api.URL, "token")
+	c.UseTransport(NewCacheTransport(dir, 0))
+	err, first := c.Get("/rest/api/2/issue/10006", map[string]string{"expand": "changelog"})
+	r.NoErrorf(err, "first run resulting to error: %s", err)
+	r.Equal(1, calls, "expected the first run to request jira")
+
+	// the second run reads the disk without any network
+	api.Close()
+
+	c = NewClient(api.URL, "token")
+	c.UseTransport(NewCacheTransport(dir, 0))
+	err, second := c.Get("/rest/api/2/issue/10006", map[string]string{"expand": "changelog"})
+	r.NoErrorf(err, "second run resulting to error: %s", err)
+	r.Equal(string(first), string(second), "cached response differs from the first one")
+	r.Equal(1, calls, "expected no request on the second run")
+
+	c.UseTransport(NewReplayTransport(dir, false))
+	err, replayed := c.Get("/rest/api/2/issue/10006", map[string]string{"expand": "changelog"})
+	r.NoErrorf(err, "replaying the cache resulting to error: %s", err)
+	r.Equal(string(first), string(replayed), "replayed response differs from the cached one")
+}
+
+func TestCacheTransport_PerHostAndUser(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"id": "10006"}`)
+	})
+	api := httptest.NewServer(handler)
+	defer api.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	for _, c := range []*JiraClient{NewClient(api.URL, "token"), NewClient(api.URL, "other-token"), NewClient(other.URL, "token")} {
+		c.UseTransport(NewCacheTransport(dir, 0))
+		err, _ := c.Get("/rest/api/2/issue/10006", nil)
+		r.NoErrorf(err, "request resulting to error: %s", err)
+	}
+	r.Equal(3, calls, "expected the responses not to be shared between hosts and users")
+
+	c := NewClient(api.URL, "token")
+	c.UseTransport(NewCacheTransport(dir, 0))
+	c.Get("/rest/api/2/issue/10006", nil)
+	r.Equal(3, calls, "expected the cached response of the same host and user")
+}
+
+func TestCacheTransport_TTLAndErrors(t *testing.T) {
+	r := require.New(t)
+
+	calls := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		if req.URL.Path == "/rest/api/2/issue/10007" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"id": "10006"}`)
+	}))
+	defer api.Close()
+
+	c := NewClient(api.URL, "token")
+	c.UseTransport(NewCacheTransport(t.TempDir(), 50*time.Millisecond))
+
+	c.Get("/rest/api/2/issue/10006", nil)
+	c.Get("/rest/api/2/issue/10006", nil)
+	r.Equal(1, calls, "expected the cached response before the ttl")
+
+	time.Sleep(60 * time.Millisecond)
+	c.Get("/rest/api/2/issue/10006", nil)
+	r.Equal(2, calls, "expected the expired response to be requested again")
+
+	for i := 0; i < 2; i++ {
+		err, _ := c.Get("/rest/api/2/issue/10007", nil)
+		r.True(IsNotFound(err), "expected not found error, got %v", err)
+	}
+	r.Equal(4, calls, "expected the error responses not to be cached")
+}
@@ -0,0 +1,106 @@
+package httprequest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ChaosTransport simulates a flaky jira for testing the retries and the deadlines: with the configured probabilities
+// it delays the requests, answers with a rate limit or server error, or drops the responses, sending the requests
+// through the Transport otherwise
+type ChaosTransport struct {
+	// Transport sends the requests, http.DefaultTransport when nil
+	Transport http.RoundTripper
+
+	// LatencyRate is the probability of delaying a request by Latency
+	LatencyRate float64
+	Latency     time.Duration
+	// FailureRate is the probability of answering with one of the StatusCodes without sending the request
+	FailureRate float64
+	// StatusCodes of the injected failures, 429 and 503 when empty
+	StatusCodes []int
+	// DropRate is the probability of losing the response of a sent request, as an unexpected EOF
+	DropRate float64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewChaosTransport gives a chaos transport sending the requests through the transport, seeded for reproducible runs
+func NewChaosTransport(transport http.RoundTripper, seed int64) *ChaosTransport {
+	return &ChaosTransport{Transport: transport, rand: rand.New(rand.NewSource(seed))}
+}
+
+// draw tells if an event of the given probability happens
+func (t *ChaosTransport) draw(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.rand == nil {
+		t.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return t.rand.Float64() < probability
+}
+
+func (t *ChaosTransport) statusCode() int {
+	codes := t.StatusCodes
+	if len(codes) == 0 {
+		codes = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return codes[t.rand.Intn(len(codes))]
+}
+
+// RoundTrip sends the request, unless a failure is injected
+func (t *ChaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.draw(t.LatencyRate) {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.Latency):
+		}
+	}
+
+	if t.draw(t.FailureRate) {
+		code := t.statusCode()
+		body := fmt.Sprintf(`{"errorMessages": ["injected %d"], "errors": {}}`, code)
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+			StatusCode:    code,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(bytes.NewBufferString(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil || !t.draw(t.DropRate) {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	return nil, io.ErrUnexpectedEOF
+}
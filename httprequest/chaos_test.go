@@ -0,0 +1,71 @@
+package httprequest
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// okTransport answers every request with 200 without any network
+type okTransport struct {
+	calls int
+}
+
+func (t *okTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(`{"ok": true}`)), Request: req}, nil
+}
+
+func TestChaosTransport_FailureRate(t *testing.T) {
+	r := require.New(t)
+
+	inner := &okTransport{}
+	chaos := NewChaosTransport(inner, 42)
+	chaos.FailureRate = 0.2
+	chaos.DropRate = 0.1
+
+	const requests = 5000
+	statuses := make(map[int]int)
+	dropped := 0
+	for i := 0; i < requests; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://your-jira-url.com/rest/api/2/field", nil)
+		resp, err := chaos.RoundTrip(req)
+		if err != nil {
+			r.Equal(io.ErrUnexpectedEOF, err, "wrong dropped response error")
+			dropped++
+			continue
+		}
+		statuses[resp.StatusCode]++
+	}
+
+	failed := statuses[http.StatusTooManyRequests] + statuses[http.StatusServiceUnavailable]
+	r.InDelta(0.2, float64(failed)/requests, 0.03, "wrong injected failure rate")
+	r.InDelta(0.8*0.1, float64(dropped)/requests, 0.02, "wrong dropped response rate")
+	r.Equal(requests-failed, inner.calls, "the failures should not be sent")
+	r.NotZero(statuses[http.StatusTooManyRequests], "expected injected rate limits")
+	r.NotZero(statuses[http.StatusServiceUnavailable], "expected injected server errors")
+}
+
+func TestChaosTransport_Retries(t *testing.T) {
+	r := require.New(t)
+
+	api, _ := newFlakyServer(t)
+	chaos := NewChaosTransport(nil, 7)
+	chaos.FailureRate = 0.5
+	chaos.LatencyRate = 0.5
+	chaos.Latency = time.Millisecond
+
+	c := NewClient(api.URL, "token")
+	c.MaxRetries = 10
+	c.RetryWait = time.Millisecond
+	c.UseTransport(chaos)
+
+	for i := 0; i < 10; i++ {
+		err, body := c.Get("/rest/api/2/field", nil)
+		r.NoErrorf(err, "expected the retries to overcome the injected failures, got %s", err)
+		r.Equal(`{"ok": true}`, string(body), "wrong body")
+	}
+}
@@ -1,22 +1,174 @@
 package httprequest
 
+import (
+	"context"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryableFunc tells if a request should be sent again, given its response or error
+type RetryableFunc func(resp *http.Response, err error) bool
+
+// DefaultRetryable retries the requests which failed on the network, were rate limited (429) or got a server error (5xx)
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if resp == nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		_, isNetErr := err.(net.Error)
+		return isNetErr || err == io.EOF || err == io.ErrUnexpectedEOF
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
 // JiraClient represents a basic API client for Jira Rest API
 type JiraClient struct {
 	URL       string
 	AuthToken string
+	// HTTPClient sends every request of the client
+	HTTPClient *http.Client
+	// UserAgent of the requests, DefaultUserAgent when empty
+	UserAgent string
+
+	// MaxRetries is the number of times a retryable request is sent again
+	MaxRetries int
+	// RetryWait is the wait before the first retry, doubled for each next one
+	RetryWait time.Duration
+	// Retryable classifies the retryable requests, DefaultRetryable when nil
+	Retryable RetryableFunc
+	// MaxResponseBytes is the size above which a response is an error, DefaultMaxResponseBytes when 0
+	MaxResponseBytes int64
+	// Deadline caps the total time of a request including its retries, no limit when 0.
+	// HTTPClient.Timeout is the limit of each attempt
+	Deadline time.Duration
 }
 
 // NewClient create a new instance of API client
 func NewClient(URL, authToken string) *JiraClient {
 	return &JiraClient{
-		URL,
-		authToken,
+		URL:        URL,
+		AuthToken:  authToken,
+		HTTPClient: &http.Client{},
+		MaxRetries: 3,
+		RetryWait:  time.Second,
 	}
 }
 
+// UseTransport sends the requests through the given transport, e.g. for mTLS or request signing
+func (c *JiraClient) UseTransport(transport http.RoundTripper) {
+	c.HTTPClient.Transport = transport
+}
+
 // Get process the Jira Rest API authenticated request
-func (c *JiraClient) Get(path string, params map[string]string) []byte {
+func (c *JiraClient) Get(path string, params map[string]string) (error, []byte) {
+	return c.Send(http.MethodGet, path, params, nil)
+}
+
+// Post sends the json body to the Jira Rest API, for the endpoints taking their input in the body
+func (c *JiraClient) Post(path string, body []byte) (error, []byte) {
+	return c.Send(http.MethodPost, path, nil, body)
+}
+
+// Send sends the request with the method, params and json body, retrying it like Get
+func (c *JiraClient) Send(method string, path string, params map[string]string, body []byte) (error, []byte) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	req := c.newRequest(ctx, path, params)
+	req.Method = method
+	req.Body = body
+
+	var response []byte
+	err := c.withRetries(ctx, func() (error, *http.Response) {
+		var err error
+		var resp *http.Response
+		err, resp, response = req.do()
+		return err, resp
+	})
+
+	return err, response
+}
+
+// DoRequest sends the request like Get but gives the response with its body unread, for the consumers streaming it
+// without buffering, e.g. attachment downloads. The caller must close the body, MaxResponseBytes is not applied
+func (c *JiraClient) DoRequest(path string, params map[string]string) (error, *http.Response) {
+	ctx, cancel := c.context()
+	req := c.newRequest(ctx, path, params)
+
+	var resp *http.Response
+	err := c.withRetries(ctx, func() (error, *http.Response) {
+		var err error
+		err, resp = req.send()
+		return err, resp
+	})
+	if err != nil {
+		cancel()
+		return err, nil
+	}
+
+	// the deadline applies until the body is closed
+	resp.Body = &cancelOnClose{resp.Body, cancel}
+	return nil, resp
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// context gives the context of a request, ending at the Deadline when set
+func (c *JiraClient) context() (context.Context, context.CancelFunc) {
+	if c.Deadline > 0 {
+		return context.WithTimeout(context.Background(), c.Deadline)
+	}
+
+	return context.WithCancel(context.Background())
+}
+
+func (c *JiraClient) newRequest(ctx context.Context, path string, params map[string]string) *HTTPRequest {
 	req := NewHTTPRequest(c.URL, path, c.AuthToken, params)
+	req.Client = c.HTTPClient
+	req.UserAgent = c.UserAgent
+	req.MaxResponseBytes = c.MaxResponseBytes
+	req.Context = ctx
+
+	return req
+}
 
-	return req.Send()
+// withRetries sends the request again while it fails with a retryable error, doubling the wait each time
+func (c *JiraClient) withRetries(ctx context.Context, send func() (error, *http.Response)) error {
+	retryable := c.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+
+	wait := c.RetryWait
+	for attempt := 0; ; attempt++ {
+		err, resp := send()
+		if attempt >= c.MaxRetries || !retryable(resp, err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = ctx.Err()
+			}
+			return errors.Wrapf(err, "deadline of %s exceeded after %d attempts", c.Deadline, attempt+1)
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
 }
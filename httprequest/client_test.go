@@ -0,0 +1,215 @@
+package httprequest
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestJiraClient_UseTransport(t *testing.T) {
+	r := require.New(t)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	defer api.Close()
+
+	transport := &recordingTransport{}
+	c := NewClient(api.URL, "token")
+	c.UseTransport(transport)
+
+	err, body := c.Get("/rest/api/2/field", nil)
+	r.NoErrorf(err, "Get resulting to error: %s", err)
+	c.Get("/rest/api/2/search", map[string]string{"jql": "project = POS"})
+
+	r.Equal(`{"ok": true}`, string(body), "wrong body")
+	r.Len(transport.requests, 2, "expected requests to go through the transport")
+	r.Equal("/rest/api/2/field", transport.requests[0].URL.Path, "wrong request path")
+	r.Equal("project = POS", transport.requests[1].URL.Query().Get("jql"), "wrong request params")
+	r.Equal("Basic token", transport.requests[1].Header.Get("Authorization"), "wrong authorization header")
+}
+
+func TestJiraClient_GetStatusError(t *testing.T) {
+	r := require.New(t)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errorMessages": ["Issue does not exist"]}`)
+	}))
+	defer api.Close()
+
+	err, _ := NewClient(api.URL, "token").Get("/rest/api/2/issue/10006", nil)
+	r.Errorf(err, "expected Get to fail")
+
+	statusErr, ok := err.(*StatusError)
+	r.True(ok, "expected a StatusError, got %T", err)
+	r.Equal(http.StatusNotFound, statusErr.StatusCode, "wrong status code")
+}
+
+// newFlakyServer gives a test server answering with the given status codes, then 200
+func newFlakyServer(t *testing.T, statusCodes ...int) (*httptest.Server, *int) {
+	calls := 0
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls <= len(statusCodes) {
+			w.WriteHeader(statusCodes[calls-1])
+			return
+		}
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	t.Cleanup(api.Close)
+
+	return api, &calls
+}
+
+func TestJiraClient_DefaultRetryable(t *testing.T) {
+	r := require.New(t)
+
+	api, calls := newFlakyServer(t, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	c := NewClient(api.URL, "token")
+	c.RetryWait = time.Millisecond
+
+	err, body := c.Get("/rest/api/2/field", nil)
+	r.NoErrorf(err, "Get resulting to error: %s", err)
+	r.Equal(`{"ok": true}`, string(body), "wrong body")
+	r.Equal(3, *calls, "expected 429 and 503 to be retried")
+
+	api, calls = newFlakyServer(t, http.StatusNotFound)
+	c = NewClient(api.URL, "token")
+	c.RetryWait = time.Millisecond
+
+	err, _ = c.Get("/rest/api/2/field", nil)
+	r.Errorf(err, "expected Get to fail")
+	r.Equal(1, *calls, "expected 404 not to be retried")
+}
+
+func TestJiraClient_CustomRetryable(t *testing.T) {
+	r := require.New(t)
+
+	only502 := func(resp *http.Response, err error) bool {
+		return resp != nil && resp.StatusCode == http.StatusBadGateway
+	}
+
+	api, calls := newFlakyServer(t, http.StatusBadGateway, http.StatusBadGateway)
+	c := NewClient(api.URL, "token")
+	c.RetryWait = time.Millisecond
+	c.Retryable = only502
+
+	err, _ := c.Get("/rest/api/2/field", nil)
+	r.NoErrorf(err, "Get resulting to error: %s", err)
+	r.Equal(3, *calls, "expected 502 to be retried")
+
+	api, calls = newFlakyServer(t, http.StatusServiceUnavailable)
+	c = NewClient(api.URL, "token")
+	c.RetryWait = time.Millisecond
+	c.Retryable = only502
+
+	err, _ = c.Get("/rest/api/2/field", nil)
+	r.Errorf(err, "expected Get to fail")
+	r.Equal(1, *calls, "expected 503 not to be retried by the custom classifier")
+}
+
+func TestJiraClient_Deadline(t *testing.T) {
+	r := require.New(t)
+
+	api, calls := newFlakyServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	c := NewClient(api.URL, "token")
+	c.RetryWait = 40 * time.Millisecond
+	c.Deadline = 100 * time.Millisecond
+
+	start := time.Now()
+	err, _ := c.Get("/rest/api/2/field", nil)
+	r.Errorf(err, "expected Get to fail")
+	r.Contains(err.Error(), "deadline of 100ms exceeded", "expected deadline error")
+	r.Contains(err.Error(), "503", "expected the last error to be kept")
+	r.True(time.Since(start) < 500*time.Millisecond, "retries should stop at the deadline")
+	r.Equal(2, *calls, "expected the retries to stop at the deadline")
+}
+
+func TestDefaultRetryable_Errors(t *testing.T) {
+	r := require.New(t)
+
+	netErr := &url.Error{Op: "Get", URL: "https://your-jira-url.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	r.True(DefaultRetryable(nil, errors.Wrap(netErr, "failed to send request")), "expected network errors to be retried")
+	r.True(DefaultRetryable(nil, &url.Error{Op: "Get", URL: "https://your-jira-url.com", Err: io.EOF}), "expected closed connections to be retried")
+	r.False(DefaultRetryable(nil, errors.New("no recorded response")), "expected other errors not to be retried")
+}
+
+func TestJiraClient_DoRequestStreaming(t *testing.T) {
+	r := require.New(t)
+
+	release := make(chan struct{})
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "first chunk\n")
+		w.(http.Flusher).Flush()
+
+		// the rest of the body is only sent once the first chunk was consumed
+		<-release
+		fmt.Fprint(w, "second chunk\n")
+	}))
+	defer api.Close()
+	defer close(release)
+
+	c := NewClient(api.URL, "token")
+	err, resp := c.DoRequest("/rest/api/2/attachment/content/10000", nil)
+	r.NoErrorf(err, "DoRequest resulting to error: %s", err)
+	defer resp.Body.Close()
+
+	first := make([]byte, len("first chunk\n"))
+	_, err = io.ReadFull(resp.Body, first)
+	r.NoError(err, "expected the first chunk before the end of the response")
+	r.Equal("first chunk\n", string(first), "wrong first chunk")
+
+	release <- struct{}{}
+	rest, err := io.ReadAll(resp.Body)
+	r.NoError(err)
+	r.Equal("second chunk\n", string(rest), "wrong second chunk")
+}
+
+func TestJiraClient_DoRequestStatusError(t *testing.T) {
+	r := require.New(t)
+
+	api, calls := newFlakyServer(t, http.StatusNotFound)
+	c := NewClient(api.URL, "token")
+
+	err, resp := c.DoRequest("/rest/api/2/attachment/content/10000", nil)
+	r.True(IsNotFound(err), "expected not found error, got %v", err)
+	r.Nil(resp, "expected no response")
+	r.Equal(1, *calls, "expected 404 not to be retried")
+}
+
+func TestJiraClient_Post(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		a.Equal(http.MethodPost, req.Method, "wrong method")
+		a.Equal("application/json", req.Header.Get("Content-Type"), "wrong content type")
+		a.Equal(`{"ids":[1,2]}`, string(body), "wrong body")
+		fmt.Fprint(w, `[]`)
+	}))
+	defer api.Close()
+
+	err, body := NewClient(api.URL, "token").Post("/rest/api/2/worklog/list", []byte(`{"ids":[1,2]}`))
+	r.NoError(err)
+	r.Equal("[]", string(body))
+}
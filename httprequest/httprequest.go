@@ -1,30 +1,131 @@
 package httprequest
 
 import (
-	"io/ioutil"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
+// defaultClient is shared by the requests without client to keep the connections alive, with its own transport
+var defaultClient = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+
 //HTTPRequest represents the apps request
 type HTTPRequest struct {
 	URL       string
 	Path      string
 	AuthToken string
 	Params    map[string]string
+	// Client sends the request, a shared one is used when nil
+	Client *http.Client
+	// UserAgent identifies the tool in the jira logs, DefaultUserAgent when empty
+	UserAgent string
+	// Context cancels the request when done, none when nil
+	Context context.Context
+	// MaxResponseBytes is the size above which the response is an error, DefaultMaxResponseBytes when 0
+	MaxResponseBytes int64
+	// Method of the request, GET when empty
+	Method string
+	// Body is sent as json, when set
+	Body []byte
+}
+
+// DefaultMaxResponseBytes is the maximum size of the responses when not set
+const DefaultMaxResponseBytes = 50 << 20
+
+// DefaultUserAgent is the user agent of the requests when not set
+const DefaultUserAgent = "ferry"
+
+// StatusError is returned when jira answers with an unsuccessful status code
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound tells if the error is a 404 answer, for an issue which was moved, deleted or archived
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
 }
 
 //Send sends the request
-func (httpreq *HTTPRequest) Send() []byte {
-	client := &http.Client{}
-	resp, err := client.Do(httpreq.get())
-	HandleError(err)
+func (httpreq *HTTPRequest) Send() (error, []byte) {
+	err, _, body := httpreq.do()
+	return err, body
+}
+
+// do sends the request and reads the whole body, the response is given for its status and headers
+func (httpreq *HTTPRequest) do() (error, *http.Response, []byte) {
+	err, resp := httpreq.send()
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			return err, resp, statusErr.Body
+		}
+		return err, resp, nil
+	}
 
 	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	HandleError(err)
 
-	return body
+	err, body := httpreq.readBody(resp)
+	if err != nil {
+		return err, resp, nil
+	}
+
+	return nil, resp, body
+}
+
+// send sends the request and gives the successful response with its body unread,
+// the body of an unsuccessful response is read in the StatusError
+func (httpreq *HTTPRequest) send() (error, *http.Response) {
+	client := httpreq.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	resp, err := client.Do(httpreq.get())
+	if err != nil {
+		return errors.Wrapf(err, "failed to send request"), nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+
+		err, body := httpreq.readBody(resp)
+		if err != nil {
+			return err, resp
+		}
+		return &StatusError{resp.StatusCode, body}, resp
+	}
+
+	return nil, resp
+}
+
+// readBody reads the whole body, up to MaxResponseBytes
+func (httpreq *HTTPRequest) readBody(resp *http.Response) (error, []byte) {
+	max := httpreq.MaxResponseBytes
+	if max <= 0 {
+		max = DefaultMaxResponseBytes
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, max+1))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read response"), nil
+	}
+
+	if int64(len(body)) > max {
+		return errors.Errorf("response larger than the limit of %d bytes", max), nil
+	}
+
+	return nil, body
 }
 
 //NewHTTPRequest ..
@@ -32,31 +133,61 @@ func NewHTTPRequest(url string, path string, authToken string, params map[string
 	return &HTTPRequest{URL: url, Path: path, AuthToken: authToken, Params: params}
 }
 
+// baseURL gives the jira url without trailing slash
+func (httpreq *HTTPRequest) baseURL() string {
+	return strings.TrimRight(httpreq.URL, "/")
+}
+
+// apiPath gives the path with exactly one leading slash
+func (httpreq *HTTPRequest) apiPath() string {
+	return "/" + strings.TrimLeft(httpreq.Path, "/")
+}
+
 func (httpreq *HTTPRequest) get() *http.Request {
-	var finalPath string
 	bearer := "Basic " + httpreq.AuthToken
-	if httpreq.Params != nil {
-		var endPoint *url.URL
-		endPoint, err := url.Parse(httpreq.URL)
-		HandleError(err)
 
-		endPoint.Path += httpreq.Path
-		parameters := url.Values{}
+	// both with and without params the url is parsed from the joined base url and path,
+	// so a context path like https://host/jira is kept the same way
+	endPoint, err := url.Parse(httpreq.baseURL() + httpreq.apiPath())
+	HandleError(err)
+
+	if httpreq.Params != nil {
+		parameters := endPoint.Query()
 
 		for k, v := range httpreq.Params {
 			parameters.Add(k, v)
 		}
 
 		endPoint.RawQuery = parameters.Encode()
-		finalPath = endPoint.String()
+	}
 
-	} else {
-		finalPath = httpreq.URL + httpreq.Path
+	ctx := httpreq.Context
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	req, err := http.NewRequest("GET", finalPath, nil)
-	req.Header.Add("Authorization", bearer)
+	method := httpreq.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if httpreq.Body != nil {
+		body = bytes.NewReader(httpreq.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endPoint.String(), body)
 	HandleError(err)
+	req.Header.Add("Authorization", bearer)
+	if httpreq.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	userAgent := httpreq.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
 
 	return req
 }
@@ -0,0 +1,136 @@
+package httprequest
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newCountingServer gives a test server counting the connections opened by its clients
+func newCountingServer(t *testing.T) (*httptest.Server, *int32) {
+	var connections int32
+
+	api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	api.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	api.Start()
+	t.Cleanup(api.Close)
+
+	return api, &connections
+}
+
+func TestHTTPRequest_ReusesConnection(t *testing.T) {
+	r := require.New(t)
+	api, connections := newCountingServer(t)
+
+	c := NewClient(api.URL, "token")
+	for i := 0; i < 10; i++ {
+		c.Get("/rest/api/2/issue/10006", nil)
+	}
+	r.EqualValues(1, atomic.LoadInt32(connections), "expected the client to reuse its connection")
+
+	for i := 0; i < 10; i++ {
+		NewHTTPRequest(api.URL, "/rest/api/2/issue/10006", "token", nil).Send()
+	}
+	r.EqualValues(2, atomic.LoadInt32(connections), "expected requests without client to reuse the connection of the default client")
+
+	transport := &recordingTransport{}
+	previous := defaultClient.Transport
+	defaultClient.Transport = transport
+	defer func() { defaultClient.Transport = previous }()
+
+	for i := 0; i < 10; i++ {
+		NewHTTPRequest(api.URL, "/rest/api/2/issue/10006", "token", nil).Send()
+	}
+	r.Len(transport.requests, 10, "expected the requests without client to be sent by the default client")
+}
+
+func BenchmarkJiraClient_Get(b *testing.B) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	defer api.Close()
+
+	c := NewClient(api.URL, "token")
+	for i := 0; i < b.N; i++ {
+		c.Get("/rest/api/2/issue/10006", nil)
+	}
+}
+
+func TestHTTPRequest_TrailingSlash(t *testing.T) {
+	r := require.New(t)
+
+	for _, base := range []string{"https://your-jira-url.com", "https://your-jira-url.com/"} {
+		for _, path := range []string{"/rest/api/2/field", "rest/api/2/field"} {
+			req := NewHTTPRequest(base, path, "token", nil).get()
+			r.Equal("https://your-jira-url.com/rest/api/2/field", req.URL.String(), "wrong url for %s and %s", base, path)
+
+			req = NewHTTPRequest(base, path, "token", map[string]string{"startAt": "0"}).get()
+			r.Equal("https://your-jira-url.com/rest/api/2/field?startAt=0", req.URL.String(), "wrong url for %s and %s", base, path)
+		}
+	}
+}
+
+func TestHTTPRequest_ContextPath(t *testing.T) {
+	r := require.New(t)
+
+	for _, base := range []string{"https://host/jira", "https://host/jira/"} {
+		req := NewHTTPRequest(base, "/rest/api/2/issue/10006", "token", nil).get()
+		r.Equal("https://host/jira/rest/api/2/issue/10006", req.URL.String(), "wrong url without params for %s", base)
+
+		req = NewHTTPRequest(base, "/rest/api/2/issue/10006", "token", map[string]string{"expand": "changelog"}).get()
+		r.Equal("https://host/jira/rest/api/2/issue/10006?expand=changelog", req.URL.String(), "wrong url with params for %s", base)
+
+		req = NewHTTPRequest(base, "/rest/api/2/issue/10006?expand=changelog", "token", map[string]string{"fields": "summary"}).get()
+		r.Equal("https://host/jira/rest/api/2/issue/10006?expand=changelog&fields=summary", req.URL.String(), "wrong url with query in path for %s", base)
+	}
+}
+
+func TestHTTPRequest_UserAgent(t *testing.T) {
+	r := require.New(t)
+
+	req := NewHTTPRequest("https://your-jira-url.com", "/rest/api/2/field", "token", nil).get()
+	r.Equal("ferry", req.Header.Get("User-Agent"), "wrong default user agent")
+
+	var userAgent string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userAgent = req.Header.Get("User-Agent")
+	}))
+	defer api.Close()
+
+	c := NewClient(api.URL, "token")
+	c.UserAgent = "ferry/1.2.0"
+	c.Get("/rest/api/2/field", nil)
+	r.Equal("ferry/1.2.0", userAgent, "wrong user agent sent")
+}
+
+func TestHTTPRequest_MaxResponseBytes(t *testing.T) {
+	r := require.New(t)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write(bytes.Repeat([]byte("x"), 2048))
+	}))
+	defer api.Close()
+
+	c := NewClient(api.URL, "token")
+	c.MaxResponseBytes = 1024
+	err, body := c.Get("/rest/api/2/field", nil)
+	r.Errorf(err, "expected response over the limit to fail")
+	r.Contains(err.Error(), "larger than the limit of 1024 bytes", "wrong error")
+	r.Nil(body, "expected no body over the limit")
+
+	c.MaxResponseBytes = 2048
+	err, body = c.Get("/rest/api/2/field", nil)
+	r.NoErrorf(err, "Get resulting to error: %s", err)
+	r.Len(body, 2048, "expected the whole body at the limit")
+}
@@ -0,0 +1,133 @@
+package httprequest
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ReplayTransport serves the responses recorded in a directory, for reproducible tests and offline demos.
+// When Record is set the requests are sent and their responses recorded instead.
+type ReplayTransport struct {
+	Dir    string
+	Record bool
+	// Transport sends the requests to record, http.DefaultTransport when nil
+	Transport http.RoundTripper
+}
+
+type fixture struct {
+	Request    string `json:"request"`
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+// NewReplayTransport gives a transport replaying, or recording, the responses of the directory
+func NewReplayTransport(dir string, record bool) *ReplayTransport {
+	return &ReplayTransport{Dir: dir, Record: record}
+}
+
+// requestKey identifies a request by its method, url with sorted params and a hash of its credentials,
+// the recordings being per host and per user
+func requestKey(req *http.Request) string {
+	key := req.Method + " " + req.URL.Scheme + "://" + req.URL.Host + req.URL.Path + "?" + req.URL.Query().Encode()
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		sum := sha1.Sum([]byte(auth))
+		key += " " + hex.EncodeToString(sum[:8])
+	}
+
+	return key
+}
+
+// fixturePath gives the file of the recorded response of the request key in the directory
+func fixturePath(dir string, key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// RoundTrip replays the recorded response of the request, or records it
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := requestKey(req)
+	if t.Record {
+		return t.record(req, key)
+	}
+
+	content, err := os.ReadFile(fixturePath(t.Dir, key))
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf("no recorded response for %s", key)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read recorded response for %s", key)
+	}
+
+	var f fixture
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse recorded response for %s", key)
+	}
+
+	return f.response(req), nil
+}
+
+// response gives the recorded response as a response to the request
+func (f fixture) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewBufferString(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}
+}
+
+func (t *ReplayTransport) record(req *http.Request, key string) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response to record for %s", key)
+	}
+
+	if err := writeFixture(t.Dir, fixture{key, resp.StatusCode, string(body)}); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// writeFixture records the response in the directory
+func writeFixture(dir string, f fixture) error {
+	content, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to record response for %s", f.Request)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create fixtures directory")
+	}
+
+	if err := os.WriteFile(fixturePath(dir, f.Request), content, 0644); err != nil {
+		return errors.Wrapf(err, "failed to record response for %s", f.Request)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package httprequest
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestReplayTransport_RecordThenReplay(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := os.MkdirTemp("", "fixtures")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, `{"startAt": 0, "total": 1, "issues": [{"key": "POS-7"}], "jql": "%s"}`, req.URL.Query().Get("jql"))
+	}))
+
+	params := map[string]string{"jql": "project = POS", "startAt": "0"}
+
+	c := NewClient(api.URL, "token")
+	c.UseTransport(NewReplayTransport(dir, true))
+	err, recorded := c.Get("/rest/api/2/search", params)
+	r.NoErrorf(err, "recording resulting to error: %s", err)
+
+	// the server is not reachable anymore, responses come from the fixtures
+	api.Close()
+
+	c = NewClient(api.URL, "token")
+	c.UseTransport(NewReplayTransport(dir, false))
+	err, replayed := c.Get("/rest/api/2/search", params)
+	r.NoErrorf(err, "replaying resulting to error: %s", err)
+	r.Equal(string(recorded), string(replayed), "replayed response differs from the recorded one")
+
+	err, _ = c.Get("/rest/api/2/search", map[string]string{"jql": "project = OTHER"})
+	r.Errorf(err, "expected unrecorded request to fail")
+	r.Contains(err.Error(), "no recorded response", "wrong unrecorded error")
+
+	c = NewClient("https://other-jira-url.com", "token")
+	c.UseTransport(NewReplayTransport(dir, false))
+	err, _ = c.Get("/rest/api/2/search", params)
+	r.Errorf(err, "expected the request of another host not to be replayed")
+	r.Contains(err.Error(), "no recorded response", "wrong other host error")
+}
@@ -436,6 +436,26 @@ func (c *JiraClient) UseStub() {
       "customId": 10026
     }
   },
+  {
+    "id": "customfield_10027",
+    "key": "customfield_10027",
+    "name": "Scrum Team",
+    "untranslatedName": "Scrum Team",
+    "custom": true,
+    "orderable": true,
+    "navigable": true,
+    "searchable": true,
+    "clauseNames": [
+      "cf[10027]",
+      "Scrum Team",
+      "Scrum Team[Dropdown]"
+    ],
+    "schema": {
+      "type": "option",
+      "custom": "com.atlassian.jira.plugin.system.customfieldtypes:select",
+      "customId": 10027
+    }
+  },
   {
     "id": "customfield_10016",
     "key": "customfield_10016",
@@ -0,0 +1,72 @@
+package jirafinder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Anonymizer replaces names by aliases like "User 1", numbered in order of first appearance
+// so the same person gets the same alias across the whole export
+type Anonymizer struct {
+	aliases map[string]string
+	// missing are the values kept as is
+	missing []string
+}
+
+// NewAnonymizer gives an anonymizer without known names, the emptyValue being the configured EmptyValue, if any
+func NewAnonymizer(emptyValue *string) *Anonymizer {
+	missing := []string{"", "N/A"}
+	if emptyValue != nil {
+		missing = append(missing, *emptyValue)
+	}
+
+	return &Anonymizer{aliases: make(map[string]string), missing: missing}
+}
+
+// Alias gives the alias of the name, empty, "N/A" and EmptyValue values are kept as is
+func (a *Anonymizer) Alias(name string) string {
+	if contains(a.missing, name) {
+		return name
+	}
+
+	alias, ok := a.aliases[name]
+	if !ok {
+		alias = fmt.Sprintf("User %d", len(a.aliases)+1)
+		a.aliases[name] = alias
+	}
+
+	return alias
+}
+
+// anonymize replaces the values of the given columns by their alias, the first row being the header.
+// Their "<field>_raw" columns, whose json holds the emails and account ids too, are blanked
+func anonymize(output [][]string, a *Anonymizer, fields []string) {
+	if len(output) == 0 {
+		return
+	}
+
+	columns := make([]int, 0)
+	raws := make([]int, 0)
+	for i, header := range output[0] {
+		for _, field := range fields {
+			if strings.EqualFold(header, field) {
+				columns = append(columns, i)
+			} else if strings.EqualFold(header, field+"_raw") {
+				raws = append(raws, i)
+			}
+		}
+	}
+
+	for _, row := range output[1:] {
+		for _, i := range columns {
+			if i < len(row) {
+				row[i] = a.Alias(row[i])
+			}
+		}
+		for _, i := range raws {
+			if i < len(row) {
+				row[i] = ""
+			}
+		}
+	}
+}
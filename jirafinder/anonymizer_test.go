@@ -0,0 +1,45 @@
+package jirafinder
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAnonymizer_SameAlias(t *testing.T) {
+	r := assert.New(t)
+
+	output := [][]string{
+		{"key", "assignee", "reporter"},
+		{"POS-1", "Jane Doe", "John Smith"},
+		{"POS-2", "John Smith", "N/A"},
+		{"POS-3", "Jane Doe", "Jane Doe"},
+	}
+
+	anonymize(output, NewAnonymizer(nil), []string{"Assignee", "reporter"})
+
+	r.EqualValues([][]string{
+		{"key", "assignee", "reporter"},
+		{"POS-1", "User 1", "User 2"},
+		{"POS-2", "User 2", "N/A"},
+		{"POS-3", "User 1", "User 1"},
+	}, output, "wrong anonymized output")
+}
+
+func TestAnonymizer_EmptyValue(t *testing.T) {
+	r := assert.New(t)
+
+	empty := "-"
+	output := [][]string{
+		{"key", "assignee"},
+		{"POS-1", "-"},
+		{"POS-2", "Jane Doe"},
+	}
+
+	anonymize(output, NewAnonymizer(&empty), []string{"assignee"})
+
+	r.EqualValues([][]string{
+		{"key", "assignee"},
+		{"POS-1", "-"},
+		{"POS-2", "User 1"},
+	}, output, "expected the EmptyValue to be kept")
+}
@@ -0,0 +1,125 @@
+package jirafinder
+
+import (
+	"sort"
+	"time"
+)
+
+// jiraTimeLayout is the layout of the dates given by jira, like "2020-08-19T20:11:37.133+0300"
+const jiraTimeLayout = "2006-01-02T15:04:05.999-0700"
+
+// FieldChange is the change of one field in the changelog of an issue
+type FieldChange struct {
+	Field   string
+	From    string
+	To      string
+	Author  string
+	Changed time.Time
+}
+
+// Changes gives the field changes of the changelog, oldest first.
+// The changelog is only filled when the issue is retrieved with "changelog" expanded
+func (i JiraIssue) Changes() []FieldChange {
+	changes := make([]FieldChange, 0)
+
+	changelog, ok := i.Data["changelog"].(map[string]interface{})
+	if !ok {
+		return changes
+	}
+
+	histories, _ := changelog["histories"].([]interface{})
+	for _, h := range histories {
+		history, ok := h.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		changed, _ := time.Parse(jiraTimeLayout, nestedString(history, "created"))
+		author := ""
+		if a, ok := history["author"].(map[string]interface{}); ok {
+			author = nestedString(a, "displayName")
+		}
+
+		items, _ := history["items"].([]interface{})
+		for _, it := range items {
+			item, ok := it.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			changes = append(changes, FieldChange{
+				Field:   nestedString(item, "field"),
+				From:    nestedString(item, "fromString"),
+				To:      nestedString(item, "toString"),
+				Author:  author,
+				Changed: changed,
+			})
+		}
+	}
+
+	sort.SliceStable(changes, func(a, b int) bool {
+		return changes[a].Changed.Before(changes[b].Changed)
+	})
+
+	return changes
+}
+
+// ChangesSince gives the field changes made after the given time, for incremental reports
+func (i JiraIssue) ChangesSince(since time.Time) []FieldChange {
+	changes := make([]FieldChange, 0)
+	for _, change := range i.Changes() {
+		if change.Changed.After(since) {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// StatusPeriod is a period the issue spent in a status, Exited being nil for the current status
+type StatusPeriod struct {
+	Status  string
+	Entered time.Time
+	Exited  *time.Time
+}
+
+// StatusTimeline gives the statuses of the issue with their entry and exit times, oldest first, e.g. for control charts.
+// The first status is entered at the creation of the issue, the changelog must be expanded
+func (i JiraIssue) StatusTimeline() []StatusPeriod {
+	_, created := i.CreatedTime()
+	timeline := make([]StatusPeriod, 0)
+
+	for _, change := range i.Changes() {
+		if change.Field != "status" {
+			continue
+		}
+
+		changed := change.Changed
+		if len(timeline) == 0 {
+			timeline = append(timeline, StatusPeriod{Status: change.From, Entered: created})
+		}
+		timeline[len(timeline)-1].Exited = &changed
+		timeline = append(timeline, StatusPeriod{Status: change.To, Entered: changed})
+	}
+
+	if len(timeline) == 0 {
+		if status := i.Status().Name; status != "" {
+			timeline = append(timeline, StatusPeriod{Status: status, Entered: created})
+		}
+	}
+
+	return timeline
+}
+
+// ReopenCount gives the number of times the issue moved from one of the done statuses back to another status,
+// the done statuses being given by JiraFinder.DoneStatuses
+func (i JiraIssue) ReopenCount(doneStatuses []string) int {
+	reopens := 0
+	for _, change := range i.Changes() {
+		if change.Field == "status" && containsFold(doneStatuses, change.From) && !containsFold(doneStatuses, change.To) {
+			reopens++
+		}
+	}
+
+	return reopens
+}
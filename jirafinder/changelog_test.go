@@ -0,0 +1,111 @@
+package jirafinder
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+const changelogIssue = `{
+  "key": "POS-7",
+  "fields": {"created": "2020-08-16T09:00:00.000+0300", "status": {"name": "Done"}},
+  "changelog": {
+    "histories": [
+      {
+        "author": {"displayName": "Jira User"},
+        "created": "2020-08-17T08:13:32.383+0300",
+        "items": [{"field": "status", "fromString": "To Do", "toString": "In Development"}]
+      },
+      {
+        "author": {"displayName": "Jira User"},
+        "created": "2020-08-19T20:11:37.133+0300",
+        "items": [
+          {"field": "status", "fromString": "In Development", "toString": "In Review"},
+          {"field": "assignee", "fromString": "Dev1", "toString": "Reviewer1"}
+        ]
+      },
+      {
+        "author": {"displayName": "Reviewer1"},
+        "created": "2020-08-21T10:00:00.000+0300",
+        "items": [{"field": "status", "fromString": "In Review", "toString": "Done"}]
+      }
+    ]
+  }
+}`
+
+func TestJiraIssue_ChangesSince(t *testing.T) {
+	r := require.New(t)
+	issue := decodeIssue(t, changelogIssue)
+
+	r.Len(issue.Changes(), 4, "wrong number of changes")
+
+	since, _ := time.Parse(time.RFC3339, "2020-08-18T00:00:00+03:00")
+	changes := issue.ChangesSince(since)
+	r.Len(changes, 3, "wrong number of changes since %s", since)
+	r.Equal("In Review", changes[0].To, "wrong first change")
+	r.Equal("assignee", changes[1].Field, "wrong second change")
+	r.Equal("Reviewer1", changes[2].Author, "wrong author of last change")
+
+	since, _ = time.Parse(time.RFC3339, "2020-08-21T10:00:00+03:00")
+	r.Empty(issue.ChangesSince(since), "expected no change after the last one")
+}
+
+func TestJiraIssue_StatusTimeline(t *testing.T) {
+	r := require.New(t)
+	issue := decodeIssue(t, changelogIssue)
+
+	at := func(value string) time.Time {
+		parsed, _ := time.Parse(time.RFC3339Nano, value)
+		return parsed
+	}
+
+	timeline := issue.StatusTimeline()
+	r.Len(timeline, 4, "wrong number of periods")
+
+	expected := []struct {
+		status  string
+		entered time.Time
+	}{
+		{"To Do", at("2020-08-16T09:00:00+03:00")},
+		{"In Development", at("2020-08-17T08:13:32.383+03:00")},
+		{"In Review", at("2020-08-19T20:11:37.133+03:00")},
+		{"Done", at("2020-08-21T10:00:00+03:00")},
+	}
+	for i, e := range expected {
+		r.Equal(e.status, timeline[i].Status, "wrong status of period %d", i)
+		r.True(e.entered.Equal(timeline[i].Entered), "wrong entry of %s: %s", e.status, timeline[i].Entered)
+		if i < len(expected)-1 {
+			r.NotNil(timeline[i].Exited, "expected %s to be exited", e.status)
+			r.True(expected[i+1].entered.Equal(*timeline[i].Exited), "wrong exit of %s: %s", e.status, timeline[i].Exited)
+		}
+	}
+	r.Nil(timeline[3].Exited, "expected the current status not to be exited")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"created": "2020-08-16T09:00:00.000+0300", "status": {"name": "To Do"}}}`)
+	timeline = issue.StatusTimeline()
+	r.Len(timeline, 1, "expected the current status only without changelog")
+	r.Equal("To Do", timeline[0].Status)
+	r.Nil(timeline[0].Exited)
+}
+
+func TestJiraIssue_ReopenCount(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{
+  "key": "POS-7",
+  "changelog": {
+    "histories": [
+      {"created": "2020-08-17T08:00:00.000+0300", "items": [{"field": "status", "fromString": "To Do", "toString": "Done"}]},
+      {"created": "2020-08-18T08:00:00.000+0300", "items": [{"field": "status", "fromString": "Done", "toString": "Reopened"}]},
+      {"created": "2020-08-19T08:00:00.000+0300", "items": [{"field": "status", "fromString": "Reopened", "toString": "Closed"}]},
+      {"created": "2020-08-20T08:00:00.000+0300", "items": [{"field": "resolution", "fromString": "Fixed", "toString": null}]},
+      {"created": "2020-08-20T08:00:00.000+0300", "items": [{"field": "status", "fromString": "Closed", "toString": "In Progress"}]},
+      {"created": "2020-08-21T08:00:00.000+0300", "items": [{"field": "status", "fromString": "In Progress", "toString": "Done"}]}
+    ]
+  }
+}`)
+
+	r.Equal(2, issue.ReopenCount([]string{"done", "Closed"}), "wrong reopen count")
+	r.Equal(1, issue.ReopenCount([]string{"Done"}), "wrong reopen count with Done only")
+	r.Equal(0, issue.ReopenCount(nil), "expected no reopen without done statuses")
+}
@@ -0,0 +1,68 @@
+package jirafinder
+
+import (
+	"sort"
+)
+
+// Diff is the difference between two search results, the keys being sorted
+type Diff struct {
+	Added   []string
+	Removed []string
+	Changed []FieldDiff
+}
+
+// FieldDiff is the change of a field of an issue found in both search results
+type FieldDiff struct {
+	Key      string
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// DiffIssues compares the fields of the issues in both results by key, e.g. to track the changes
+// between two exports of the same search
+func DiffIssues(oldIssues []JiraIssue, newIssues []JiraIssue, fields []string) Diff {
+	diff := Diff{Added: make([]string, 0), Removed: make([]string, 0), Changed: make([]FieldDiff, 0)}
+
+	oldByKey := issuesByKey(oldIssues)
+	newByKey := issuesByKey(newIssues)
+
+	for key, o := range oldByKey {
+		n, ok := newByKey[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, key)
+			continue
+		}
+
+		for _, field := range fields {
+			oldValue := getValueFromField(o.Data, field)
+			newValue := getValueFromField(n.Data, field)
+			if oldValue != newValue {
+				diff.Changed = append(diff.Changed, FieldDiff{Key: key, Field: field, OldValue: oldValue, NewValue: newValue})
+			}
+		}
+	}
+
+	for key := range newByKey {
+		if _, ok := oldByKey[key]; !ok {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.SliceStable(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Key < diff.Changed[j].Key
+	})
+
+	return diff
+}
+
+func issuesByKey(issues []JiraIssue) map[string]JiraIssue {
+	byKey := make(map[string]JiraIssue, len(issues))
+	for _, issue := range issues {
+		byKey[issue.Key()] = issue
+	}
+
+	return byKey
+}
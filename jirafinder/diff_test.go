@@ -0,0 +1,29 @@
+package jirafinder
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDiffIssues(t *testing.T) {
+	r := require.New(t)
+
+	oldIssues := []JiraIssue{
+		decodeIssue(t, `{"key": "POS-1", "fields": {"summary": "Fix login", "status": {"name": "Open"}}}`),
+		decodeIssue(t, `{"key": "POS-2", "fields": {"summary": "Add export", "status": {"name": "Open"}}}`),
+	}
+	newIssues := []JiraIssue{
+		decodeIssue(t, `{"key": "POS-1", "fields": {"summary": "Fix login", "status": {"name": "Done"}}}`),
+		decodeIssue(t, `{"key": "POS-3", "fields": {"summary": "Update docs", "status": {"name": "Open"}}}`),
+	}
+
+	diff := DiffIssues(oldIssues, newIssues, []string{"summary", "status"})
+	r.Equal([]string{"POS-3"}, diff.Added, "wrong added issues")
+	r.Equal([]string{"POS-2"}, diff.Removed, "wrong removed issues")
+	r.Equal([]FieldDiff{{Key: "POS-1", Field: "status", OldValue: "Open", NewValue: "Done"}}, diff.Changed, "wrong changed fields")
+
+	diff = DiffIssues(oldIssues, oldIssues, []string{"summary", "status"})
+	r.Empty(diff.Added, "expected no added issue")
+	r.Empty(diff.Removed, "expected no removed issue")
+	r.Empty(diff.Changed, "expected no change")
+}
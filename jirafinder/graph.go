@@ -0,0 +1,116 @@
+package jirafinder
+
+import (
+	"sort"
+)
+
+// Graph is the dependency graph of issues, an edge going from an issue to the ones it blocks
+// or, for the other link types, to the ones it links outward
+type Graph struct {
+	Nodes []string
+	Edges map[string][]string
+}
+
+// BuildDependencyGraph builds the graph from the "issuelinks" field of the issues, linked issues
+// outside of the given ones are part of the graph too
+func BuildDependencyGraph(issues []JiraIssue) Graph {
+	nodes := make(map[string]bool)
+	edges := make(map[string]map[string]bool)
+	addEdge := func(from string, to string) {
+		nodes[from], nodes[to] = true, true
+		if edges[from] == nil {
+			edges[from] = make(map[string]bool)
+		}
+		edges[from][to] = true
+	}
+
+	for _, issue := range issues {
+		key := issue.Key()
+		if key == "" {
+			continue
+		}
+		nodes[key] = true
+
+		links, _ := issue.field("issuelinks").([]interface{})
+		for _, l := range links {
+			link, ok := l.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if outward, ok := link["outwardIssue"].(map[string]interface{}); ok {
+				addEdge(key, nestedString(outward, "key"))
+			}
+			if inward, ok := link["inwardIssue"].(map[string]interface{}); ok {
+				addEdge(nestedString(inward, "key"), key)
+			}
+		}
+	}
+
+	g := Graph{Nodes: make([]string, 0, len(nodes)), Edges: make(map[string][]string)}
+	for node := range nodes {
+		g.Nodes = append(g.Nodes, node)
+	}
+	sort.Strings(g.Nodes)
+
+	for from, to := range edges {
+		for node := range to {
+			g.Edges[from] = append(g.Edges[from], node)
+		}
+		sort.Strings(g.Edges[from])
+	}
+
+	return g
+}
+
+// Cycle gives the keys of a dependency cycle, the first key being repeated at the end, nil without cycle
+func (g Graph) Cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int)
+	path := make([]string, 0)
+
+	var visit func(node string) []string
+	visit = func(node string) []string {
+		state[node] = visiting
+		path = append(path, node)
+
+		for _, next := range g.Edges[node] {
+			switch state[next] {
+			case visiting:
+				for i, n := range path {
+					if n == next {
+						return append(append([]string{}, path[i:]...), next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[node] = visited
+		return nil
+	}
+
+	for _, node := range g.Nodes {
+		if state[node] == unvisited {
+			if cycle := visit(node); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// HasCycle tells if some issues are blocking each other
+func (g Graph) HasCycle() bool {
+	return g.Cycle() != nil
+}
@@ -0,0 +1,41 @@
+package jirafinder
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBuildDependencyGraph_Chain(t *testing.T) {
+	r := require.New(t)
+
+	issues := []JiraIssue{
+		decodeIssue(t, `{"key": "POS-1", "fields": {"issuelinks": [
+  {"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "outwardIssue": {"key": "POS-2"}}
+]}}`),
+		decodeIssue(t, `{"key": "POS-2", "fields": {"issuelinks": [
+  {"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "inwardIssue": {"key": "POS-1"}},
+  {"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "outwardIssue": {"key": "POS-3"}}
+]}}`),
+		decodeIssue(t, `{"key": "POS-3", "fields": {"issuelinks": []}}`),
+	}
+
+	g := BuildDependencyGraph(issues)
+	r.Equal([]string{"POS-1", "POS-2", "POS-3"}, g.Nodes, "wrong nodes")
+	r.Equal(map[string][]string{"POS-1": {"POS-2"}, "POS-2": {"POS-3"}}, g.Edges, "wrong edges")
+	r.False(g.HasCycle(), "expected no cycle in a chain")
+	r.Nil(g.Cycle(), "expected no cycle in a chain")
+}
+
+func TestBuildDependencyGraph_Cycle(t *testing.T) {
+	r := require.New(t)
+
+	issues := []JiraIssue{
+		decodeIssue(t, `{"key": "POS-1", "fields": {"issuelinks": [{"type": {"name": "Blocks"}, "outwardIssue": {"key": "POS-2"}}]}}`),
+		decodeIssue(t, `{"key": "POS-2", "fields": {"issuelinks": [{"type": {"name": "Blocks"}, "outwardIssue": {"key": "POS-3"}}]}}`),
+		decodeIssue(t, `{"key": "POS-3", "fields": {"issuelinks": [{"type": {"name": "Blocks"}, "outwardIssue": {"key": "POS-1"}}]}}`),
+	}
+
+	g := BuildDependencyGraph(issues)
+	r.True(g.HasCycle(), "expected a cycle")
+	r.Equal([]string{"POS-1", "POS-2", "POS-3", "POS-1"}, g.Cycle(), "wrong cycle")
+}
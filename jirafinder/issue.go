@@ -0,0 +1,496 @@
+package jirafinder
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status is the workflow status of an issue, with its category: "new", "indeterminate" or "done"
+type Status struct {
+	Name         string
+	CategoryKey  string
+	CategoryName string
+}
+
+// Parent is the parent issue of a sub task
+type Parent struct {
+	Key     string
+	Summary string
+	Status  Status
+}
+
+// Transition is a workflow transition of an issue, moving it to the To status
+type Transition struct {
+	ID   string
+	Name string
+	To   Status
+}
+
+// parseTransitions gives the transitions of the issue retrieved with "transitions" expanded
+func parseTransitions(issue map[string]interface{}) []Transition {
+	values, _ := issue["transitions"].([]interface{})
+
+	transitions := make([]Transition, 0, len(values))
+	for _, v := range values {
+		value, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		transitions = append(transitions, Transition{
+			ID:   nestedString(value, "id"),
+			Name: nestedString(value, "name"),
+			To:   parseStatus(value["to"]),
+		})
+	}
+
+	return transitions
+}
+
+// Comment is a comment of an issue
+type Comment struct {
+	Author  string
+	Body    string
+	Created string
+	// Visibility restricts the comment to a group or role, nil for public comments
+	Visibility *Visibility
+}
+
+// Visibility is the restriction of a comment, Type being "group" or "role"
+type Visibility struct {
+	Type  string
+	Value string
+}
+
+// field gives the raw value of the field from the 'fields' property of the issue, nil when absent
+func (i JiraIssue) field(name string) interface{} {
+	fields, ok := i.Data["fields"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return fields[name]
+}
+
+// nestedInt gives the number held by the key of a nested field object, 0 when absent
+func (i JiraIssue) nestedInt(name string, key string) int {
+	obj, ok := i.field(name).(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	if val, ok := obj[key].(float64); ok {
+		return int(val)
+	}
+
+	return 0
+}
+
+// Key gives the key of the issue, like "POS-7"
+func (i JiraIssue) Key() string {
+	key, _ := i.Data["key"].(string)
+	return key
+}
+
+// Votes gives the number of votes of the issue
+func (i JiraIssue) Votes() int {
+	return i.nestedInt("votes", "votes")
+}
+
+// Watchers gives the number of users watching the issue
+func (i JiraIssue) Watchers() int {
+	return i.nestedInt("watches", "watchCount")
+}
+
+// nestedString gives the string held by the key of an object, empty when absent
+func nestedString(obj map[string]interface{}, key string) string {
+	val, _ := obj[key].(string)
+	return val
+}
+
+// Status gives the status of the issue, the zero value when the issue has no status
+func (i JiraIssue) Status() Status {
+	return parseStatus(unwrapSingle(i.field("status")))
+}
+
+// User is a jira user, the display name and email may be hidden by the privacy settings leaving only the account id
+type User struct {
+	AccountID    string
+	Name         string
+	DisplayName  string
+	EmailAddress string
+}
+
+// String gives the display name of the user, falling back to the user name then to the account id
+func (u User) String() string {
+	for _, name := range []string{u.DisplayName, u.Name, u.AccountID} {
+		if name != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func parseUser(val interface{}) User {
+	user, ok := val.(map[string]interface{})
+	if !ok {
+		return User{}
+	}
+
+	return User{
+		AccountID:    nestedString(user, "accountId"),
+		Name:         nestedString(user, "name"),
+		DisplayName:  nestedString(user, "displayName"),
+		EmailAddress: nestedString(user, "emailAddress"),
+	}
+}
+
+// AssigneeEmail gives the email of the assignee, empty when unassigned or when the email is hidden by the privacy settings
+func (i JiraIssue) AssigneeEmail() string {
+	return parseUser(unwrapSingle(i.field("assignee"))).EmailAddress
+}
+
+// Reporter gives the user who reported the issue, who can be changed unlike the creator
+func (i JiraIssue) Reporter() User {
+	return parseUser(unwrapSingle(i.field("reporter")))
+}
+
+// Creator gives the user who created the issue
+func (i JiraIssue) Creator() User {
+	return parseUser(unwrapSingle(i.field("creator")))
+}
+
+// IssueType gives the type of the issue, which tells if it is a sub task whatever the name of the type
+func (i JiraIssue) IssueType() IssueType {
+	issueType, ok := i.field("issuetype").(map[string]interface{})
+	if !ok {
+		return IssueType{}
+	}
+
+	subtask, _ := issueType["subtask"].(bool)
+	return IssueType{
+		ID:             nestedString(issueType, "id"),
+		Name:           nestedString(issueType, "name"),
+		Subtask:        subtask,
+		HierarchyLevel: i.nestedInt("issuetype", "hierarchyLevel"),
+	}
+}
+
+// IsFlagged tells if the issue is flagged as impeded, the flagged field id differing between jira instances.
+// The field is null or a list of options, like [{"value": "Impediment"}], a single option being accepted too
+func (i JiraIssue) IsFlagged(field string) bool {
+	switch val := i.field(field).(type) {
+	case []interface{}:
+		return len(val) > 0
+	case map[string]interface{}:
+		return len(val) > 0
+	}
+
+	return false
+}
+
+// defaultStoryPointsFields are the usual names of the story points field, "Story point estimate" on the team managed projects
+var defaultStoryPointsFields = []string{"Story Points", "Story point estimate"}
+
+// StoryPoints gives the story points of the issue, false when not estimated. The field is looked up by the given
+// id or display name first, then by the default names, the display names being known when "names" is expanded
+func (i JiraIssue) StoryPoints(field string) (float64, bool) {
+	candidates := defaultStoryPointsFields
+	if field != "" {
+		candidates = append([]string{field}, candidates...)
+	}
+
+	for _, name := range candidates {
+		switch val := unwrapSingle(i.fieldByName(name)).(type) {
+		case float64:
+			return val, true
+		case string:
+			if points, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+				return points, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// fieldByName gives the raw value of the field from its id or display name, nil when absent
+func (i JiraIssue) fieldByName(name string) interface{} {
+	if val := i.field(name); val != nil {
+		return val
+	}
+
+	for id, display := range i.Names {
+		if strings.EqualFold(display, name) {
+			return i.field(id)
+		}
+	}
+
+	return nil
+}
+
+// TimeField parses the date time field, given by jira like "2020-08-19T20:11:37.133+0300"
+func (i JiraIssue) TimeField(name string) (error, time.Time) {
+	value, ok := unwrapSingle(i.field(name)).(string)
+	if !ok {
+		return errors.Errorf("field %s is not set", name), time.Time{}
+	}
+
+	t, err := time.Parse(jiraTimeLayout, value)
+	if err != nil {
+		return errors.Wrapf(err, "invalid time '%s' of field %s", value, name), time.Time{}
+	}
+
+	return nil, t
+}
+
+// CreatedTime gives the creation time of the issue
+func (i JiraIssue) CreatedTime() (error, time.Time) {
+	return i.TimeField("created")
+}
+
+// UpdatedTime gives the last update time of the issue
+func (i JiraIssue) UpdatedTime() (error, time.Time) {
+	return i.TimeField("updated")
+}
+
+// ResolutionTime gives the resolution time of the issue, an error for the unresolved issues
+func (i JiraIssue) ResolutionTime() (error, time.Time) {
+	return i.TimeField("resolutiondate")
+}
+
+// DueDate gives the due date of the issue at midnight in the local time zone, false when not set
+func (i JiraIssue) DueDate() (time.Time, bool) {
+	value, ok := unwrapSingle(i.field("duedate")).(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	due, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return due, true
+}
+
+// IsOverdue tells if the due date of the issue is past while the issue is not done, the due day itself not being overdue
+func (i JiraIssue) IsOverdue() bool {
+	return i.overdueAt(time.Now())
+}
+
+func (i JiraIssue) overdueAt(now time.Time) bool {
+	due, ok := i.DueDate()
+	if !ok || i.Status().CategoryKey == "done" {
+		return false
+	}
+
+	return !now.Before(due.AddDate(0, 0, 1))
+}
+
+// Labels gives the labels of the issue
+func (i JiraIssue) Labels() []string {
+	labels := make([]string, 0)
+
+	list := wrapList(i.field("labels"))
+	for _, l := range list {
+		if label, ok := l.(string); ok {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels
+}
+
+// Phase gives the report phase of the current status from the status to phase map, the status name when not mapped
+func (i JiraIssue) Phase(phases map[string]string) string {
+	name := i.Status().Name
+	if phase, ok := phases[name]; ok {
+		return phase
+	}
+
+	for status, phase := range phases {
+		if strings.EqualFold(status, name) {
+			return phase
+		}
+	}
+
+	return name
+}
+
+func parseStatus(val interface{}) Status {
+	status, ok := val.(map[string]interface{})
+	if !ok {
+		return Status{}
+	}
+
+	s := Status{Name: nestedString(status, "name")}
+	if category, ok := status["statusCategory"].(map[string]interface{}); ok {
+		s.CategoryKey = nestedString(category, "key")
+		s.CategoryName = nestedString(category, "name")
+	}
+
+	return s
+}
+
+// Parent gives the parent of a sub task, the zero value for the issues without parent
+func (i JiraIssue) Parent() Parent {
+	parent, ok := i.field("parent").(map[string]interface{})
+	if !ok {
+		return Parent{}
+	}
+
+	p := Parent{Key: nestedString(parent, "key")}
+	if fields, ok := parent["fields"].(map[string]interface{}); ok {
+		p.Summary = nestedString(fields, "summary")
+		p.Status = parseStatus(fields["status"])
+	}
+
+	return p
+}
+
+// SecurityLevel gives the name of the security level of the issue, empty when not set
+func (i JiraIssue) SecurityLevel() string {
+	security, ok := i.field("security").(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	return nestedString(security, "name")
+}
+
+// Comments gives the comments of the issue, filled when the "comment" field is retrieved
+func (i JiraIssue) Comments() []Comment {
+	comments := make([]Comment, 0)
+
+	field, ok := i.field("comment").(map[string]interface{})
+	if !ok {
+		return comments
+	}
+
+	list, _ := field["comments"].([]interface{})
+	for _, c := range list {
+		if comment, ok := c.(map[string]interface{}); ok {
+			comments = append(comments, parseComment(comment))
+		}
+	}
+
+	return comments
+}
+
+func parseComment(comment map[string]interface{}) Comment {
+	c := Comment{Body: nestedString(comment, "body"), Created: nestedString(comment, "created")}
+	if author, ok := comment["author"].(map[string]interface{}); ok {
+		c.Author = nestedString(author, "displayName")
+	}
+
+	if visibility, ok := comment["visibility"].(map[string]interface{}); ok {
+		c.Visibility = &Visibility{Type: nestedString(visibility, "type"), Value: nestedString(visibility, "value")}
+	}
+
+	return c
+}
+
+// Description gives the description as plain text, the markup of the v3 api document format being stripped
+func (i JiraIssue) Description() string {
+	return textFromField(i.field("description"))
+}
+
+// Environment gives the environment of the issue as plain text, like the description
+func (i JiraIssue) Environment() string {
+	return textFromField(i.field("environment"))
+}
+
+// textFromField gives the text of a long text field, either a string (v2 api) or a document (v3 api)
+func textFromField(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		return strings.TrimSpace(adfToText(v))
+	}
+
+	return ""
+}
+
+// adfBlocks are the nodes of the atlassian document format rendered on their own lines
+var adfBlocks = map[string]bool{
+	"paragraph": true, "heading": true, "listItem": true, "codeBlock": true, "blockquote": true, "rule": true,
+}
+
+// adfToText gives the text of an atlassian document format node and its children
+func adfToText(node map[string]interface{}) string {
+	var b strings.Builder
+
+	switch nestedString(node, "type") {
+	case "text":
+		b.WriteString(nestedString(node, "text"))
+	case "hardBreak":
+		b.WriteString("\n")
+	case "mention", "emoji":
+		if attrs, ok := node["attrs"].(map[string]interface{}); ok {
+			b.WriteString(nestedString(attrs, "text"))
+		}
+	}
+
+	content, _ := node["content"].([]interface{})
+	for _, c := range content {
+		if child, ok := c.(map[string]interface{}); ok {
+			b.WriteString(adfToText(child))
+		}
+	}
+
+	if adfBlocks[nestedString(node, "type")] {
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// secondsField gives the number of seconds of a time field, 0 when not set
+func (i JiraIssue) secondsField(name string) int64 {
+	if val, ok := i.field(name).(float64); ok {
+		return int64(val)
+	}
+
+	return 0
+}
+
+// OriginalEstimateSeconds gives the original estimate of the issue in seconds
+func (i JiraIssue) OriginalEstimateSeconds() int64 {
+	return i.secondsField("timeoriginalestimate")
+}
+
+// TimeSpentSeconds gives the time logged on the issue in seconds
+func (i JiraIssue) TimeSpentSeconds() int64 {
+	return i.secondsField("timespent")
+}
+
+// AggregateOriginalEstimateSeconds gives the original estimate rolled up by jira over the issue and its sub tasks, in seconds
+func (i JiraIssue) AggregateOriginalEstimateSeconds() int64 {
+	return i.secondsField("aggregatetimeoriginalestimate")
+}
+
+// AggregateTimeSpentSeconds gives the time logged on the issue and its sub tasks as rolled up by jira, in seconds
+func (i JiraIssue) AggregateTimeSpentSeconds() int64 {
+	return i.secondsField("aggregatetimespent")
+}
+
+// SubtaskCompletion gives the ratio of sub tasks in a done status, 0 without sub tasks
+func (i JiraIssue) SubtaskCompletion() float64 {
+	if len(i.SubTasks) == 0 {
+		return 0
+	}
+
+	done := 0
+	for _, subTask := range i.SubTasks {
+		if subTask.Status.CategoryKey == "done" {
+			done++
+		}
+	}
+
+	return float64(done) / float64(len(i.SubTasks))
+}
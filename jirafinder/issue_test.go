@@ -0,0 +1,341 @@
+package jirafinder
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// decodeIssue builds an issue from its json representation
+func decodeIssue(t *testing.T, body string) JiraIssue {
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(body), &data), "invalid issue json")
+
+	return JiraIssue{Data: data}
+}
+
+func TestJiraIssue_VotesAndWatchers(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{
+  "key": "POS-7",
+  "fields": {
+    "votes": {"self": "https://your-jira-url.com/rest/api/2/issue/POS-7/votes", "votes": 3, "hasVoted": false},
+    "watches": {"self": "https://your-jira-url.com/rest/api/2/issue/POS-7/watchers", "watchCount": 5, "isWatching": true}
+  }
+}`)
+	r.Equal(3, issue.Votes(), "wrong votes")
+	r.Equal(5, issue.Watchers(), "wrong watchers")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"votes": null}}`)
+	r.Equal(0, issue.Votes(), "expected no votes")
+	r.Equal(0, issue.Watchers(), "expected no watchers")
+}
+
+func TestJiraIssue_Status(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{
+  "key": "POS-7",
+  "fields": {
+    "status": {
+      "name": "In Development",
+      "id": "10001",
+      "statusCategory": {"id": 4, "key": "indeterminate", "colorName": "yellow", "name": "In Progress"}
+    }
+  }
+}`)
+	r.Equal(Status{Name: "In Development", CategoryKey: "indeterminate", CategoryName: "In Progress"}, issue.Status(), "wrong status")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {}}`)
+	r.Equal(Status{}, issue.Status(), "expected empty status")
+}
+
+func TestJiraIssue_Parent(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{
+  "key": "POS-18",
+  "fields": {
+    "issuetype": {"name": "Sub-task", "subtask": true},
+    "parent": {
+      "id": "10006",
+      "key": "POS-7",
+      "fields": {
+        "summary": "Dashboard components",
+        "status": {"name": "To Do", "statusCategory": {"key": "new", "name": "To Do"}}
+      }
+    }
+  }
+}`)
+	r.Equal(Parent{Key: "POS-7", Summary: "Dashboard components", Status: Status{Name: "To Do", CategoryKey: "new", CategoryName: "To Do"}}, issue.Parent(), "wrong parent")
+
+	issue = decodeIssue(t, `{"key": "POS-7", "fields": {"issuetype": {"name": "Story", "subtask": false}}}`)
+	r.Equal(Parent{}, issue.Parent(), "expected no parent")
+}
+
+func TestJiraIssue_SecurityLevel(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{
+  "key": "POS-7",
+  "fields": {
+    "security": {"id": "10000", "name": "Internal only", "description": "Staff members only"},
+    "comment": {
+      "comments": [
+        {"author": {"displayName": "Jira User"}, "body": "Public note", "created": "2020-08-19T20:11:37.133+0300"},
+        {"author": {"displayName": "Jira User"}, "body": "Admins note", "created": "2020-08-19T20:12:37.133+0300",
+         "visibility": {"type": "role", "value": "Administrators"}}
+      ],
+      "total": 2
+    }
+  }
+}`)
+	r.Equal("Internal only", issue.SecurityLevel(), "wrong security level")
+
+	comments := issue.Comments()
+	r.Len(comments, 2, "wrong number of comments")
+	r.Nil(comments[0].Visibility, "expected public comment")
+	r.Equal(&Visibility{Type: "role", Value: "Administrators"}, comments[1].Visibility, "wrong comment visibility")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"security": null}}`)
+	r.Equal("", issue.SecurityLevel(), "expected no security level")
+	r.Empty(issue.Comments(), "expected no comments")
+}
+
+func TestJiraIssue_DescriptionV2(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"description": "Steps:\n1. open the dashboard"}}`)
+	r.Equal("Steps:\n1. open the dashboard", issue.Description(), "wrong v2 description")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"description": null}}`)
+	r.Equal("", issue.Description(), "expected empty description")
+}
+
+func TestJiraIssue_Environment(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"environment": "Chrome 85, Windows 10"}}`)
+	r.Equal("Chrome 85, Windows 10", issue.Environment(), "wrong v2 environment")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"environment": {"type": "doc", "version": 1, "content": [
+  {"type": "paragraph", "content": [{"type": "text", "text": "Safari 14"}]},
+  {"type": "paragraph", "content": [{"type": "text", "text": "macOS Big Sur"}]}
+]}}}`)
+	r.Equal("Safari 14\nmacOS Big Sur", issue.Environment(), "wrong v3 environment")
+
+	issue = decodeIssue(t, `{"key": "POS-9", "fields": {"environment": null}}`)
+	r.Equal("", issue.Environment(), "expected empty environment")
+}
+
+func TestJiraIssue_DescriptionV3(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{
+  "key": "POS-7",
+  "fields": {
+    "description": {
+      "type": "doc",
+      "version": 1,
+      "content": [
+        {"type": "paragraph", "content": [
+          {"type": "text", "text": "Dashboard is "},
+          {"type": "text", "text": "broken", "marks": [{"type": "strong"}]}
+        ]},
+        {"type": "paragraph", "content": [
+          {"type": "text", "text": "Reported by "},
+          {"type": "mention", "attrs": {"id": "557058:114f", "text": "@Jira User"}}
+        ]}
+      ]
+    }
+  }
+}`)
+	r.Equal("Dashboard is broken\nReported by @Jira User", issue.Description(), "wrong v3 description")
+	r.Equal("Dashboard is broken\nReported by @Jira User", getValueFromField(issue.Data, "description"), "wrong extracted v3 description")
+}
+
+func TestJiraIssue_TimeSeconds(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"timeoriginalestimate": 28800, "timespent": 5400, "timetracking": {"originalEstimate": "1d"}}}`)
+	r.EqualValues(28800, issue.OriginalEstimateSeconds(), "wrong original estimate")
+	r.EqualValues(5400, issue.TimeSpentSeconds(), "wrong time spent")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"timeoriginalestimate": null}}`)
+	r.EqualValues(0, issue.OriginalEstimateSeconds(), "expected no original estimate")
+	r.EqualValues(0, issue.TimeSpentSeconds(), "expected no time spent")
+}
+
+func TestJiraIssue_AggregateTimes(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"timespent": 5400, "aggregatetimeoriginalestimate": 57600, "aggregatetimespent": 12600}}`)
+	r.EqualValues(57600, issue.AggregateOriginalEstimateSeconds(), "wrong aggregate original estimate")
+	r.EqualValues(12600, issue.AggregateTimeSpentSeconds(), "wrong aggregate time spent")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"aggregatetimeoriginalestimate": null, "aggregatetimespent": null}}`)
+	r.EqualValues(0, issue.AggregateOriginalEstimateSeconds(), "expected no aggregate original estimate")
+	r.EqualValues(0, issue.AggregateTimeSpentSeconds(), "expected no aggregate time spent")
+}
+
+func TestJiraIssue_AssigneeEmail(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"assignee": {"accountId": "5b10a2844c20165700ede21g", "displayName": "Jane Doe", "emailAddress": "jane@example.com"}}}`)
+	r.Equal("jane@example.com", issue.AssigneeEmail(), "wrong assignee email")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"assignee": {"accountId": "5b10ac8d82e05b22cc7d4ef5", "displayName": "John Roe"}}}`)
+	r.Equal("", issue.AssigneeEmail(), "expected no email when hidden")
+
+	issue = decodeIssue(t, `{"key": "POS-9", "fields": {"assignee": null}}`)
+	r.Equal("", issue.AssigneeEmail(), "expected no email when unassigned")
+}
+
+func TestJiraIssue_ReporterCreator(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {
+  "reporter": {"accountId": "5b10a2844c20165700ede21g", "displayName": "Jane Doe", "emailAddress": "jane@example.com"},
+  "creator": {"accountId": "5b10ac8d82e05b22cc7d4ef5"}
+}}`)
+	r.Equal("Jane Doe", issue.Reporter().String(), "wrong reporter")
+	r.Equal("jane@example.com", issue.Reporter().EmailAddress, "wrong reporter email")
+	r.Equal("5b10ac8d82e05b22cc7d4ef5", issue.Creator().String(), "creator without display name should give its account id")
+	r.Empty(issue.Creator().DisplayName, "expected hidden creator display name")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"reporter": null}}`)
+	r.Equal(User{}, issue.Reporter(), "expected no reporter")
+	r.Equal(User{}, issue.Creator(), "expected no creator")
+}
+
+func TestJiraIssue_IssueType(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-18", "fields": {"issuetype": {"id": "10002", "name": "Dev Task", "subtask": true, "hierarchyLevel": -1}}}`)
+	r.Equal(IssueType{ID: "10002", Name: "Dev Task", Subtask: true, HierarchyLevel: -1}, issue.IssueType(), "wrong sub task type")
+
+	issue = decodeIssue(t, `{"key": "POS-7", "fields": {"issuetype": {"id": "10001", "name": "Story", "subtask": false}}}`)
+	r.False(issue.IssueType().Subtask, "story should not be a sub task")
+}
+
+func TestJiraIssue_StoryPoints(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"customfield_10016": 3}}`)
+	issue.Names = map[string]string{"customfield_10016": "Story Points"}
+	points, ok := issue.StoryPoints("")
+	r.True(ok, "expected story points from the default name")
+	r.Equal(3.0, points, "wrong int story points")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"customfield_10026": 2.5}}`)
+	points, ok = issue.StoryPoints("customfield_10026")
+	r.True(ok, "expected story points from the configured id")
+	r.Equal(2.5, points, "wrong float story points")
+
+	issue = decodeIssue(t, `{"key": "POS-9", "fields": {"customfield_10026": "5"}}`)
+	issue.Names = map[string]string{"customfield_10026": "Story point estimate"}
+	points, ok = issue.StoryPoints("")
+	r.True(ok, "expected story points from a numeric string")
+	r.Equal(5.0, points, "wrong string story points")
+
+	for _, missing := range []string{`{}`, `{"customfield_10016": null}`, `{"customfield_10016": "n/a"}`} {
+		issue = decodeIssue(t, `{"key": "POS-10", "fields": `+missing+`}`)
+		issue.Names = map[string]string{"customfield_10016": "Story Points"}
+		_, ok = issue.StoryPoints("")
+		r.Falsef(ok, "expected no story points for %s", missing)
+	}
+}
+
+func TestJiraIssue_DueDate(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"duedate": "2020-08-25", "status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}}}}`)
+	due, ok := issue.DueDate()
+	r.True(ok, "expected a due date")
+	r.Equal(time.Date(2020, time.August, 25, 0, 0, 0, 0, time.Local), due, "wrong due date")
+	r.True(issue.IsOverdue(), "expected past due date to be overdue")
+	r.False(issue.overdueAt(time.Date(2020, time.August, 25, 18, 0, 0, 0, time.Local)), "expected the due day not to be overdue")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"duedate": "2020-08-25", "status": {"name": "Done", "statusCategory": {"key": "done"}}}}`)
+	r.False(issue.IsOverdue(), "expected done issue not to be overdue")
+
+	future := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	issue = decodeIssue(t, `{"key": "POS-9", "fields": {"duedate": "`+future+`", "status": {"name": "To Do", "statusCategory": {"key": "new"}}}}`)
+	r.False(issue.IsOverdue(), "expected future due date not to be overdue")
+
+	issue = decodeIssue(t, `{"key": "POS-10", "fields": {"duedate": null, "status": {"name": "To Do", "statusCategory": {"key": "new"}}}}`)
+	_, ok = issue.DueDate()
+	r.False(ok, "expected no due date")
+	r.False(issue.IsOverdue(), "expected issue without due date not to be overdue")
+}
+
+func TestJiraIssue_IsFlagged(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"customfield_10021": [{"id": "10019", "value": "Impediment"}]}}`)
+	r.True(issue.IsFlagged("customfield_10021"), "expected flagged issue")
+	r.False(issue.IsFlagged("customfield_10050"), "expected other field not to be flagged")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"customfield_10050": {"id": "10019", "value": "Impediment"}}}`)
+	r.True(issue.IsFlagged("customfield_10050"), "expected flagged issue with a single option")
+
+	for _, unflagged := range []string{`null`, `[]`} {
+		issue = decodeIssue(t, `{"key": "POS-9", "fields": {"customfield_10021": `+unflagged+`}}`)
+		r.Falsef(issue.IsFlagged("customfield_10021"), "expected %s not to be flagged", unflagged)
+	}
+}
+
+func TestJiraIssue_CreatedTime(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"created": "2020-08-17T08:13:32.383+0300", "updated": "2020-08-19T20:11:40.821+0300", "resolutiondate": null}}`)
+
+	err, created := issue.CreatedTime()
+	r.NoErrorf(err, "CreatedTime resulting to error: %s", err)
+	r.True(time.Date(2020, 8, 17, 5, 13, 32, 383000000, time.UTC).Equal(created), "wrong created time %s", created)
+
+	err, updated := issue.UpdatedTime()
+	r.NoErrorf(err, "UpdatedTime resulting to error: %s", err)
+	r.True(updated.After(created), "expected update after creation")
+
+	err, _ = issue.ResolutionTime()
+	r.EqualError(err, "field resolutiondate is not set", "expected unresolved issue error")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"created": "17/Aug/20"}}`)
+	err, _ = issue.CreatedTime()
+	r.Error(err, "expected invalid time error")
+	r.Contains(err.Error(), "invalid time '17/Aug/20' of field created", "wrong invalid time error")
+}
+
+func TestJiraIssue_Phase(t *testing.T) {
+	r := require.New(t)
+
+	phases := map[string]string{"To Do": "Backlog", "In Development": "In Progress", "Code Review": "Review", "Closed": "Done"}
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"status": {"name": "In Development"}}}`)
+	r.Equal("In Progress", issue.Phase(phases), "wrong mapped phase")
+
+	issue = decodeIssue(t, `{"key": "POS-8", "fields": {"status": {"name": "code review"}}}`)
+	r.Equal("Review", issue.Phase(phases), "status should be matched ignoring case")
+
+	issue = decodeIssue(t, `{"key": "POS-9", "fields": {"status": {"name": "Blocked"}}}`)
+	r.Equal("Blocked", issue.Phase(phases), "unmapped status should be kept")
+	r.Equal("Blocked", issue.Phase(nil), "status should be kept without phases")
+}
+
+func TestJiraIssue_SubtaskCompletion(t *testing.T) {
+	r := require.New(t)
+
+	done := Status{Name: "Done", CategoryKey: "done"}
+	inProgress := Status{Name: "In Development", CategoryKey: "indeterminate"}
+
+	issue := JiraIssue{SubTasks: []SubTask{{Status: done}, {Status: inProgress}, {Status: done}, {Status: inProgress}}}
+	r.Equal(0.5, issue.SubtaskCompletion(), "wrong completion")
+
+	issue = JiraIssue{SubTasks: []SubTask{{Status: done}, {Status: done}, {Status: inProgress}}}
+	r.InDelta(0.667, issue.SubtaskCompletion(), 0.001, "wrong completion")
+
+	r.Equal(0.0, JiraIssue{}.SubtaskCompletion(), "expected no completion without sub tasks")
+}
@@ -6,28 +6,44 @@ import (
 	"github.com/gojira/ferry/config"
 	"github.com/pkg/errors"
 	"log"
+	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	httprequest "github.com/gojira/ferry/httprequest"
 )
 
-type keyPairValue struct {
-	key   string
-	value string
-}
+type SearchResult struct {
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	Total      int               `json:"total"`
+	Issues     []interface{}     `json:"issues"`
+	Names      map[string]string `json:"names"`
+
+	NextPageToken string `json:"nextPageToken"`
+	IsLast        bool   `json:"isLast"`
 
-type fieldParam struct {
-	key  int
-	name string
+	// WarningMessages are given instead of failing on invalid jql parts with "validateQuery": "warn"
+	WarningMessages []string `json:"warningMessages"`
 }
 
-type SearchResult struct {
-	StartAt    int           `json:"startAt"`
-	MaxResults int           `json:"maxResults"`
-	Total      int           `json:"total"`
-	Issues     []interface{} `json:"issues"`
+// merge appends the issues, names and new warnings of the next page
+func (r *SearchResult) merge(page *SearchResult) {
+	r.Issues = append(r.Issues, page.Issues...)
+	for _, warning := range page.WarningMessages {
+		if !contains(r.WarningMessages, warning) {
+			r.WarningMessages = append(r.WarningMessages, warning)
+		}
+	}
+	for id, name := range page.Names {
+		if r.Names == nil {
+			r.Names = make(map[string]string)
+		}
+		r.Names[id] = name
+	}
 }
 
 type SubTask struct {
@@ -35,6 +51,14 @@ type SubTask struct {
 	AssigneeName string
 	TotalHours   string
 	Name         string
+	ParentKey    string
+	Status       Status
+
+	OriginalEstimateSeconds int64
+	TimeSpentSeconds        int64
+
+	// FetchError is set when the sub task could not be retrieved
+	FetchError error
 }
 
 type JiraIssue struct {
@@ -42,16 +66,67 @@ type JiraIssue struct {
 	SubTasks     []SubTask
 	Fields       []string
 	AssigneeName string
+	// Names maps the field ids to their display names, filled when "names" is expanded
+	Names map[string]string
+	// RemoteLinks are filled when IncludeRemoteLinks is set
+	RemoteLinks []RemoteLink
+	// Transitions available from the current status, filled when IncludeTransitions is set
+	Transitions []Transition
+	// Components are filled with their lead and assignee type when IncludeComponentDetails is set
+	Components []Component
+	// CurrentSprint is filled when SprintField is set, nil for the issues without sprint
+	CurrentSprint *Sprint
+	// Stale is set when a sub task was not found anymore while enriching the issue, moved or deleted since
+	Stale bool
+	// NewKey is the current key of an issue retrieved by the key it had before being moved to another project
+	NewKey string
+}
+
+// RenderedField gives the html rendered value of the field, filled when "renderedFields" is expanded
+func (i JiraIssue) RenderedField(field string) string {
+	rendered, ok := i.Data["renderedFields"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	if val, ok := rendered[field].(string); ok {
+		return val
+	}
+
+	return ""
 }
 
 // JiraFinder finds the issue from jira based on the config
 type JiraFinder struct {
 	Config    config.Configuration
 	api       *httprequest.JiraClient
-	filtersCh chan keyPairValue
-	fieldsCh  chan fieldParam
 	fieldKeys []string
-	mu        sync.RWMutex
+	// sources are the fields requested for the computed columns of the fieldKeys
+	sources map[string][]string
+	mu      sync.RWMutex
+	// fields caches the fields of the instance, retrieved once
+	fields []map[string]interface{}
+	// issueKeys matches the issue keys in free text
+	issueKeys *regexp.Regexp
+	// components caches the components by id
+	components map[string]Component
+
+	// Errors holds the errors of the issues which could not be processed when ContinueOnError is set
+	Errors map[string]error
+
+	// Transformer remaps the exported values, when set
+	Transformer ValueTransformer
+
+	// Warnings are the warning messages of the last search, e.g. for the fields which can't be searched
+	// when the "validateQuery" search param is "warn"
+	Warnings []string
+
+	// Processors are run in order on each issue after its enrichment, e.g. to compute derived fields
+	Processors []Processor
+
+	// Progress is called after each fetched page of search results, when set.
+	// total is 0 with token pagination as the endpoint does not give it
+	Progress func(fetched, total int)
 }
 
 func NewJiraFinderFomFile(configFile string) (error, *JiraFinder) {
@@ -69,15 +144,68 @@ func NewJiraFinder(c *config.Configuration) (error, *JiraFinder) {
 		return errors.New("no config file found. Set the config first before searching using SetConfig() func"), nil
 	}
 
+	switch c.TimeTrackingField {
+	case "", "originalEstimate", "remainingEstimate", "timeSpent":
+	default:
+		return errors.Errorf("invalid TimeTrackingField '%s', expected originalEstimate, remainingEstimate or timeSpent", c.TimeTrackingField), nil
+	}
+
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		return errors.Wrapf(err, "invalid TimeZone '%s'", c.TimeZone), nil
+	}
+
+	for name, value := range c.Filters {
+		switch value.(type) {
+		case string, bool, int, int64, uint64, float64:
+		default:
+			return errors.Errorf("invalid filter '%s': %v is not a single value", name, value), nil
+		}
+	}
+
+	switch strings.ToLower(c.Deployment) {
+	case "", "cloud", "server":
+	default:
+		return errors.Errorf("invalid Deployment '%s', expected cloud or server", c.Deployment), nil
+	}
+
+	issueKeys := defaultIssueKeyPattern
+	if c.IssueKeyPattern != "" {
+		var err error
+		if issueKeys, err = regexp.Compile(c.IssueKeyPattern); err != nil {
+			return errors.Wrapf(err, "invalid IssueKeyPattern '%s'", c.IssueKeyPattern), nil
+		}
+	}
+
+	api := httprequest.NewClient(c.JiraURL, c.AuthToken)
+	api.UserAgent = c.UserAgent
+	api.MaxResponseBytes = c.MaxResponseBytes
+
+	if c.Deadline != "" {
+		deadline, err := time.ParseDuration(c.Deadline)
+		if err != nil {
+			return errors.Wrapf(err, "invalid Deadline '%s'", c.Deadline), nil
+		}
+		api.Deadline = deadline
+	}
+
+	if c.CacheDir != "" {
+		var ttl time.Duration
+		if c.CacheTTL != "" {
+			var err error
+			if ttl, err = time.ParseDuration(c.CacheTTL); err != nil {
+				return errors.Wrapf(err, "invalid CacheTTL '%s'", c.CacheTTL), nil
+			}
+		}
+		api.UseTransport(httprequest.NewCacheTransport(c.CacheDir, ttl))
+	}
+
 	return nil, &JiraFinder{
 		Config: *c,
-		api:    httprequest.NewClient(c.JiraURL, c.AuthToken),
-
-		filtersCh: make(chan keyPairValue),
-		fieldsCh:  make(chan fieldParam),
+		api:    api,
 
 		fieldKeys: make([]string, len(c.FieldsToRetrieve)),
 		mu:        sync.RWMutex{},
+		issueKeys: issueKeys,
 	}
 }
 
@@ -86,46 +214,172 @@ func (f *JiraFinder) UseStub() {
 	f.api.UseStub()
 }
 
+// defaultAPIPath is the prefix of the jira rest api paths when not configured
+const defaultAPIPath = "/rest/api/2"
+
+// apiPath prefixes the path with the configured rest api path
+func (f *JiraFinder) apiPath(path string) string {
+	prefix := f.Config.APIPath
+	if prefix == "" {
+		prefix = defaultAPIPath
+	}
+
+	return strings.TrimRight(prefix, "/") + path
+}
+
+// UseTransport sends the requests to jira through the given transport
+func (f *JiraFinder) UseTransport(transport http.RoundTripper) {
+	f.api.UseTransport(transport)
+}
+
+// UseRetryable decides which failed requests to jira are sent again
+func (f *JiraFinder) UseRetryable(retryable httprequest.RetryableFunc) {
+	f.api.Retryable = retryable
+}
+
 //Search finds the issue from jira based on the config
 func (f *JiraFinder) Search() error {
-	output := [][]string{f.Config.FieldsToRetrieve}
-
 	err, out := f.produceFields()
 	if err != nil {
 		return err
 	}
 
 	filters, fields := f.processFields(out)
-	err, response := f.search(filters, fields)
+
+	// the fields are sent by id, the unknown ones failing the search instead of being dropped from the columns
+	requested := make([]string, 0, len(f.Config.FieldsToRetrieve))
+	positions := make([]int, 0, len(f.Config.FieldsToRetrieve))
+	for i, column := range f.Config.FieldsToRetrieve {
+		if _, ok := f.sourceFields(column, out); !ok {
+			requested = append(requested, column)
+			positions = append(positions, i)
+		}
+	}
+	err, ids, labels := f.NormalizeFields(requested)
 	if err != nil {
 		return err
 	}
 
-	issues := f.prepareIssueObjects(response, fields)
-	issueCh := f.processIssues(issues)
+	f.mu.Lock()
+	for i, id := range ids {
+		fields[positions[i]] = id
+	}
+	f.fieldKeys = fields
+	f.mu.Unlock()
+
+	columns := make([]string, len(fields))
+	for i, key := range fields {
+		columns[i] = f.Config.FieldsToRetrieve[i]
+		if label, ok := labels[key]; ok {
+			columns[i] = label
+		}
+	}
+	output := [][]string{header(columns, f.Config.RawColumns)}
 
-	count := 0
-	for i := range issueCh {
-		if i != nil {
-			if f := download(*i); f != nil {
-				output = append(output, f)
-			}
+	jql := getJql(filters)
+	if f.Config.FilterID != "" {
+		if err, jql = f.GetFilterJQL(f.Config.FilterID); err != nil {
+			return err
 		}
+	}
+
+	// the pages are enriched as they arrive, the search waiting for the workers when they are behind
+	issues := make(chan JiraIssue, f.workers())
+	searched := make(chan error, 1)
+	var response *SearchResult
+	go func() {
+		defer close(issues)
+
+		var err error
+		err, response = f.searchPages(jql, fields, func(page *SearchResult) {
+			for _, issue := range f.prepareIssueObjects(page, fields) {
+				issues <- issue
+			}
+		})
+		searched <- err
+	}()
+
+	err, enriched := f.enrichStream(issues)
+	if searchErr := <-searched; searchErr != nil {
+		return searchErr
+	}
+	if err != nil {
+		return err
+	}
+
+	f.Warnings = response.WarningMessages
+	for _, warning := range f.Warnings {
+		log.Printf("warning: %s", warning)
+	}
 
-		count++
-		if count == response.Total {
-			close(issueCh)
+	for _, i := range enriched {
+		if f := download(i, f.Config); f != nil {
+			output = append(output, f)
 		}
 	}
 
+	f.postProcess(output, NewAnonymizer(f.Config.EmptyValue))
+
+	if f.Config.PruneEmptyColumns {
+		output = pruneEmptyColumns(output, f.Config.EmptyValue)
+	}
+
 	return writeToCsv(output, f.Config.DownloadPath)
 }
 
+// postProcess applies the Transformer and the anonymization to the rows, the first row being the header
+func (f *JiraFinder) postProcess(output [][]string, a *Anonymizer) {
+	if f.Transformer != nil {
+		transformValues(output, f.Transformer)
+	}
+
+	if len(f.Config.AnonymizeFields) > 0 {
+		anonymize(output, a, f.Config.AnonymizeFields)
+	}
+}
+
+// WarmFieldCache retrieves the fields of the instance up front, the searches resolving their fields
+// and filters without any request then, e.g. for a batch of searches
+func (f *JiraFinder) WarmFieldCache() error {
+	err, fields := f.fetchFields()
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.fields = fields
+	f.mu.Unlock()
+
+	return nil
+}
+
+// produceFields gives the fields of the instance, retrieved on the first call only
 func (f *JiraFinder) produceFields() (error, []map[string]interface{}) {
-	body := f.api.Get("/rest/api/2/field", nil)
+	f.mu.RLock()
+	fields := f.fields
+	f.mu.RUnlock()
+	if fields != nil {
+		return nil, fields
+	}
+
+	if err := f.WarmFieldCache(); err != nil {
+		return err, nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return nil, f.fields
+}
+
+func (f *JiraFinder) fetchFields() (error, []map[string]interface{}) {
+	err, body := f.get(f.apiPath("/field"), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve fields"), nil
+	}
 
 	var fields []map[string]interface{}
-	err := json.Unmarshal(body, &fields)
+	err = json.Unmarshal(body, &fields)
 	if err != nil {
 		return errors.Wrap(err, "failed to build fields"), nil
 	}
@@ -133,71 +387,179 @@ func (f *JiraFinder) produceFields() (error, []map[string]interface{}) {
 	return nil, fields
 }
 
-func (f *JiraFinder) collectParams(kpDestination map[string]string) {
-	for {
-		select {
-		case kv, open := <-f.filtersCh:
-			if open {
-				kpDestination[kv.key] = kv.value
-			}
+// GetCustomFields gives the id of each custom field by its name, like "Story Points": "customfield_10016"
+func (f *JiraFinder) GetCustomFields() (error, map[string]string) {
+	err, fields := f.produceFields()
+	if err != nil {
+		return err, nil
+	}
 
-		case fp, open := <-f.fieldsCh:
-			if open && fp.name != "" {
-				f.addField(fp)
-			}
+	customFields := make(map[string]string)
+	for _, field := range fields {
+		if custom, _ := field["custom"].(bool); !custom {
+			continue
 		}
+
+		name, _ := field["name"].(string)
+		id, _ := field["id"].(string)
+		customFields[name] = id
 	}
+
+	return nil, customFields
 }
 
-func (f *JiraFinder) processFields(fields []map[string]interface{}) (map[string]string, []string) {
+// NormalizeFields resolves the requested fields, given by name or by id, to the ids sent to the search
+// along with the requested name of each id to label the columns, unknown fields are an error
+func (f *JiraFinder) NormalizeFields(requested []string) (error, []string, map[string]string) {
+	err, fields := f.produceFields()
+	if err != nil {
+		return err, nil, nil
+	}
 
-	filters := make(map[string]string)
+	aliased := make([]string, 0, len(requested))
+	for _, r := range requested {
+		aliased = append(aliased, f.fieldAlias(r))
+	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(fields))
+	err, ids := normalizeFields(fields, aliased)
+	if err != nil {
+		return err, nil, nil
+	}
+
+	labels := make(map[string]string)
+	for i, id := range ids {
+		labels[id] = requested[i]
+	}
 
-	go f.collectParams(filters)
+	return nil, ids, labels
+}
 
-	for _, field := range fields {
-		go func(field map[string]interface{}) {
-			defer wg.Done()
+func normalizeFields(fields []map[string]interface{}, requested []string) (error, []string) {
+	ids := make([]string, 0, len(requested))
+
+	for _, r := range requested {
+		var id string
+		for _, field := range fields {
+			if _, ok := field["name"].(string); ok && matchField(field, r) {
+				id, _ = field["id"].(string)
+				break
+			}
+		}
 
-			for k, v := range f.Config.Filters {
-				if strings.ToLower(field["name"].(string)) == strings.ToLower(k) {
-					key := k
-					if field["custom"].(bool) {
-						key = "cf[" + strings.Replace(field["id"].(string), "customfield_", "", -1) + "]"
-					}
-					f.filtersCh <- keyPairValue{key, v.(string)}
+		if id == "" {
+			return errors.Errorf("unknown field %s", r), nil
+		}
+
+		ids = append(ids, id)
+	}
+
+	return nil, ids
+}
+
+// processFields resolves the configured filters and fields to the jql keys and the field ids of the search
+func (f *JiraFinder) processFields(fields []map[string]interface{}) (map[string]string, []string) {
+	filters := make(map[string]string)
+	keys := make([]string, len(f.Config.FieldsToRetrieve))
+	sources := make(map[string][]string)
+	for i, v := range f.Config.FieldsToRetrieve {
+		// the computed columns, like the fallback fields or the phase, are exported from their source fields
+		if source, ok := f.sourceFields(v, fields); ok {
+			keys[i] = v
+			sources[v] = source
+		}
+	}
+
+	for _, field := range fields {
+		for k, v := range f.Config.Filters {
+			if name := f.fieldAlias(k); matchField(field, name) {
+				key := name
+				if field["custom"].(bool) {
+					key = "cf[" + strings.Replace(field["id"].(string), "customfield_", "", -1) + "]"
 				}
+				filters[key] = filterValue(v)
+			}
+		}
+
+		for i, v := range f.Config.FieldsToRetrieve {
+			if _, ok := sources[v]; ok {
+				continue
 			}
 
-			for i, v := range f.Config.FieldsToRetrieve {
-				if strings.ToLower(field["name"].(string)) == strings.ToLower(v) {
-					val := v
-					if field["custom"].(bool) {
-						val = fmt.Sprint(field["id"].(string))
-					}
-					f.fieldsCh <- fieldParam{i, val}
+			if name := f.fieldAlias(v); matchField(field, name) {
+				val := name
+				if field["custom"].(bool) {
+					val = fmt.Sprint(field["id"].(string))
 				}
+				keys[i] = val
 			}
-		}(field)
+		}
 	}
 
-	wg.Wait()
-
-	close(f.filtersCh)
-	close(f.fieldsCh)
 	clean(filters)
 
-	return filters, f.fieldKeys
+	f.mu.Lock()
+	f.fieldKeys = keys
+	f.sources = sources
+	f.mu.Unlock()
+
+	return filters, keys
 }
 
-func (f *JiraFinder) addField(field fieldParam) {
-	f.mu.Lock()
-	defer f.mu.Unlock()
+// sourceFields gives the fields to request for a column computed when exported, false for the other columns
+func (f *JiraFinder) sourceFields(column string, fields []map[string]interface{}) ([]string, bool) {
+	if candidates, ok := f.Config.FieldFallbacks[column]; ok {
+		return candidates, true
+	}
 
-	f.fieldKeys[field.key] = field.name
+	switch {
+	case column == "key", column == "bug count", column == "complexity":
+		// the key is always returned and the sub tasks of the counts are retrieved by the enrichment
+		return nil, true
+	case column == "phase":
+		return []string{"status"}, true
+	case column == "flagged" && f.Config.FlaggedField != "":
+		return []string{f.Config.FlaggedField}, true
+	case column == "storypoints":
+		return storyPointsFields(fields, f.Config.StoryPointsField), true
+	}
+
+	return nil, false
+}
+
+// storyPointsFields gives the ids of the instance fields matching the configured story points field or the default names
+func storyPointsFields(fields []map[string]interface{}, configured string) []string {
+	candidates := defaultStoryPointsFields
+	if configured != "" {
+		candidates = append([]string{configured}, candidates...)
+	}
+
+	ids := make([]string, 0)
+	for _, candidate := range candidates {
+		for _, field := range fields {
+			if id, _ := field["id"].(string); matchField(field, candidate) && !contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids
+}
+
+// fieldAlias gives the current name of a renamed field from the FieldAliases, the name itself otherwise
+func (f *JiraFinder) fieldAlias(name string) string {
+	for old, current := range f.Config.FieldAliases {
+		if strings.EqualFold(old, name) {
+			return current
+		}
+	}
+
+	return name
+}
+
+// matchField tells if the field has the given name, or the given id when aliased to an id
+func matchField(field map[string]interface{}, name string) bool {
+	id, _ := field["id"].(string)
+	return strings.EqualFold(field["name"].(string), name) || strings.EqualFold(id, name)
 }
 
 func (f *JiraFinder) setFields(params map[string]string) {
@@ -205,22 +567,76 @@ func (f *JiraFinder) setFields(params map[string]string) {
 	defer f.mu.Unlock()
 
 	// prevent data race
-	params["fields"] = strings.Join(f.fieldKeys, ",")
+	fields := make([]string, 0, len(f.fieldKeys))
+	for _, key := range f.fieldKeys {
+		if source, ok := f.sources[key]; ok {
+			fields = append(fields, source...)
+			continue
+		}
+		fields = append(fields, key)
+	}
+	params["fields"] = strings.Join(fields, ",")
 }
 
-func (f *JiraFinder) search(filters map[string]string, fields []string) (error, *SearchResult) {
+// GetFilterJQL gives the JQL of the saved filter with the given id
+func (f *JiraFinder) GetFilterJQL(filterID string) (error, string) {
+	var filter struct {
+		JQL string `json:"jql"`
+	}
+
+	err, body := f.get(f.apiPath("/filter/"+filterID), nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve filter %s", filterID), ""
+	}
+
+	if err := json.Unmarshal(body, &filter); err != nil {
+		return errors.Wrapf(err, "failed to retrieve filter %s", filterID), ""
+	}
+
+	if filter.JQL == "" {
+		return errors.Errorf("filter %s has no jql", filterID), ""
+	}
+
+	return nil, filter.JQL
+}
+
+func (f *JiraFinder) search(jql string, fields []string) (error, *SearchResult) {
+	return f.searchPages(jql, fields, nil)
+}
+
+// searchPages gives each page of results to the page func as it arrives, when set, and all the results at the end
+func (f *JiraFinder) searchPages(jql string, fields []string, page func(*SearchResult)) (error, *SearchResult) {
+	if err := validateJql(jql); err != nil {
+		return err, nil
+	}
+
 	var step int64 = 100
 	var startAt int64 = 0
 	params := make(map[string]string)
-	params["jql"] = getJql(filters)
+	params["jql"] = jql
 	params["maxResults"] = strconv.FormatInt(step, 10)
-	params["startAt"] = strconv.FormatInt(startAt, 10)
+	if len(f.Config.Expand) > 0 {
+		params["expand"] = strings.Join(f.Config.Expand, ",")
+	}
+	if f.Config.FieldsByKeys {
+		params["fieldsByKeys"] = "true"
+	}
 	f.setFields(params)
+	mergeParams(params, f.Config.SearchParams)
+
+	if f.Config.TokenPagination {
+		return f.searchByToken(params, page)
+	}
 
-	err, result := f.doSearchByParams(params)
+	params["startAt"] = strconv.FormatInt(startAt, 10)
+	err, result := f.doSearchByParams(f.apiPath("/search"), params)
 	if err != nil {
 		return err, nil
 	}
+	f.reportProgress(len(result.Issues), result.Total)
+	if page != nil {
+		page(result)
+	}
 
 	// handle results over the limit of 100
 	for {
@@ -231,21 +647,248 @@ func (f *JiraFinder) search(filters map[string]string, fields []string) (error,
 		startAt += step
 		params["startAt"] = strconv.FormatInt(startAt, 10)
 
-		err, r := f.doSearchByParams(params)
+		err, r := f.doSearchByParams(f.apiPath("/search"), params)
+		if err != nil {
+			return err, nil
+		}
+
+		result.merge(r)
+		f.reportProgress(len(result.Issues), result.Total)
+		if page != nil {
+			page(r)
+		}
+	}
+
+	return nil, result
+}
+
+// jqlDateLayout is the layout of the dates in jql queries
+const jqlDateLayout = "2006/01/02 15:04"
+
+// dateWindows splits the period in consecutive windows of the step, the last one ending at the end of the period
+func dateWindows(start time.Time, end time.Time, step time.Duration) [][2]time.Time {
+	windows := make([][2]time.Time, 0)
+	if step <= 0 {
+		return windows
+	}
+
+	for from := start; from.Before(end); from = from.Add(step) {
+		to := from.Add(step)
+		if to.After(end) {
+			to = end
+		}
+		windows = append(windows, [2]time.Time{from, to})
+	}
+
+	return windows
+}
+
+// SearchWindowed runs one search per window of updated dates between start and end, instead of a single
+// large search, and gives the issues of all the windows without duplicates
+func (f *JiraFinder) SearchWindowed(jql string, start time.Time, end time.Time, step time.Duration) (error, []JiraIssue) {
+	issues := make([]JiraIssue, 0)
+
+	for _, window := range dateWindows(start, end, step) {
+		windowJql := fmt.Sprintf(`updated >= "%s" AND updated < "%s"`, window[0].Format(jqlDateLayout), window[1].Format(jqlDateLayout))
+		if jql != "" {
+			windowJql = "(" + jql + ") AND " + windowJql
+		}
+
+		err, result := f.search(windowJql, f.fieldKeys)
+		if err != nil {
+			return err, nil
+		}
+
+		issues = append(issues, f.prepareIssueObjects(result, f.fieldKeys)...)
+	}
+
+	return nil, DedupeIssues(issues)
+}
+
+// SearchMany runs the named queries concurrently, up to Workers at a time, and gives the issues by query name
+// along with the errors of the failed queries by name
+func (f *JiraFinder) SearchMany(queries map[string]string) (map[string]error, map[string][]JiraIssue) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(map[string]error)
+	results := make(map[string][]JiraIssue)
+	sem := make(chan struct{}, f.workers())
+
+	for name, jql := range queries {
+		wg.Add(1)
+		go func(name string, jql string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			err, result := f.search(jql, f.fieldKeys)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[name] = errors.Wrapf(err, "query %s failed", name)
+				return
+			}
+			results[name] = f.prepareIssueObjects(result, f.fieldKeys)
+		}(name, jql)
+	}
+
+	wg.Wait()
+
+	return errs, results
+}
+
+// searchByToken follows the nextPageToken of the Jira Cloud search endpoint until the last page
+func (f *JiraFinder) searchByToken(params map[string]string, page func(*SearchResult)) (error, *SearchResult) {
+	result := new(SearchResult)
+
+	for {
+		err, r := f.doSearchByParams(f.apiPath("/search/jql"), params)
 		if err != nil {
 			return err, nil
 		}
 
-		result.Issues = append(result.Issues, r.Issues...)
+		result.merge(r)
+		f.reportProgress(len(result.Issues), result.Total)
+		if page != nil {
+			page(r)
+		}
+
+		if r.NextPageToken == "" || r.IsLast {
+			break
+		}
+		params["nextPageToken"] = r.NextPageToken
 	}
 
+	// the token based endpoint does not give a total
+	result.Total = len(result.Issues)
+
 	return nil, result
 }
 
-func (f *JiraFinder) doSearchByParams(params map[string]string) (error, *SearchResult) {
+// defaultMaxJqlLength is the maximum length of the generated jql when MaxJqlLength is not set
+const defaultMaxJqlLength = 4000
+
+// SearchByKeys gives the issues of the keys, the "key in" jql being split in several searches
+// when longer than MaxJqlLength
+func (f *JiraFinder) SearchByKeys(keys []string) (error, []JiraIssue) {
+	maxLength := f.Config.MaxJqlLength
+	if maxLength <= 0 {
+		maxLength = defaultMaxJqlLength
+	}
+
+	issues := make([]JiraIssue, 0, len(keys))
+	for _, jql := range keysJql(keys, maxLength) {
+		err, result := f.search(jql, f.fieldKeys)
+		if err != nil {
+			return err, nil
+		}
+
+		issues = append(issues, f.prepareIssueObjects(result, f.fieldKeys)...)
+	}
+
+	return nil, issues
+}
+
+// defaultIssueKeyPattern matches the issue keys of the default project key format, like "POS-7"
+var defaultIssueKeyPattern = regexp.MustCompile(`[A-Z][A-Z0-9]+-\d+`)
+
+// ExtractIssueKeys gives the issue keys found in the text, like a commit log, in their order without duplicates,
+// to search them with SearchByKeys. The keys are matched by the IssueKeyPattern when configured
+func (f *JiraFinder) ExtractIssueKeys(text string) []string {
+	pattern := f.issueKeys
+	if pattern == nil {
+		pattern = defaultIssueKeyPattern
+	}
+
+	keys := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, key := range pattern.FindAllString(text, -1) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// keysJql gives the "key in" jql of the keys, split in chunks of at most maxLength characters
+func keysJql(keys []string, maxLength int) []string {
+	queries := make([]string, 0)
+	chunk := make([]string, 0)
+	length := len("key in ()")
+
+	for _, key := range keys {
+		if len(chunk) > 0 && length+len(",")+len(key) > maxLength {
+			queries = append(queries, "key in ("+strings.Join(chunk, ",")+")")
+			chunk, length = chunk[:0], len("key in ()")
+		}
+
+		if len(chunk) > 0 {
+			length += len(",")
+		}
+		chunk = append(chunk, key)
+		length += len(key)
+	}
+
+	if len(chunk) > 0 {
+		queries = append(queries, "key in ("+strings.Join(chunk, ",")+")")
+	}
+
+	return queries
+}
+
+// SearchIssueIDs gives only the ids of the issues matching the jql, which is much cheaper than a full search
+func (f *JiraFinder) SearchIssueIDs(jql string) (error, []string) {
+	if err := validateJql(jql); err != nil {
+		return err, nil
+	}
+
+	ids := make([]string, 0)
+	params := map[string]string{"jql": jql, "maxResults": "100"}
+
+	for {
+		var page struct {
+			IssueIDs      []json.Number `json:"issueIds"`
+			NextPageToken string        `json:"nextPageToken"`
+		}
+
+		err, body := f.get(f.apiPath("/search/id"), params)
+		if err != nil {
+			return errors.Wrapf(err, "failed to search issue ids"), nil
+		}
+
+		if err := json.Unmarshal(body, &page); err != nil {
+			return errors.Wrapf(err, "failed to parse issue ids search response"), nil
+		}
+
+		for _, id := range page.IssueIDs {
+			ids = append(ids, id.String())
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		params["nextPageToken"] = page.NextPageToken
+	}
+
+	return nil, ids
+}
+
+func (f *JiraFinder) reportProgress(fetched, total int) {
+	if f.Progress != nil {
+		f.Progress(fetched, total)
+	}
+}
+
+func (f *JiraFinder) doSearchByParams(path string, params map[string]string) (error, *SearchResult) {
 	result := new(SearchResult)
 
-	body := f.api.Get("/rest/api/2/search", params)
+	err, body := f.get(path, params)
+	if err != nil {
+		return errors.Wrapf(err, "failed to search issues"), nil
+	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
 		return errors.Wrapf(err, "failed to parse search API response"), nil
@@ -255,68 +898,316 @@ func (f *JiraFinder) doSearchByParams(params map[string]string) (error, *SearchR
 }
 
 func (f *JiraFinder) prepareIssueObjects(result *SearchResult, fields []string) []JiraIssue {
+	names := result.Names
+	if names == nil {
+		// the display names are known from the fields of the instance when "names" is not expanded
+		names = f.fieldNames()
+	}
+
 	ji := make([]JiraIssue, 0)
 	for _, rawIssue := range result.Issues {
 		if issue, ok := rawIssue.(map[string]interface{}); ok {
-			ji = append(ji, JiraIssue{Data: issue, Fields: fields})
+			ji = append(ji, JiraIssue{Data: issue, Fields: fields, Names: names})
 		}
 	}
 
 	return ji
 }
 
-func (f *JiraFinder) processIssues(issues []JiraIssue) chan *JiraIssue {
+// fieldNames gives the display names of the cached fields by id, nil before the fields are retrieved
+func (f *JiraFinder) fieldNames() map[string]string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 
-	out := make(chan *JiraIssue, 100)
-	for i, issue := range issues {
-		go func(issue JiraIssue, i int) {
-			issueID := issue.Data["id"].(string)
-			err, parent := f.getIssue(issueID, true)
+	if f.fields == nil {
+		return nil
+	}
 
-			if err != nil {
-				log.Printf("error while processing issue %s: %s", issueID, err)
-				out <- nil
-				return
+	names := make(map[string]string, len(f.fields))
+	for _, field := range f.fields {
+		id, _ := field["id"].(string)
+		names[id], _ = field["name"].(string)
+	}
+
+	return names
+}
+
+type enrichResult struct {
+	key   string
+	issue *JiraIssue
+	err   error
+}
+
+// defaultWorkers is the number of issues enriched concurrently when not configured
+const defaultWorkers = 10
+
+// processIssues enriches the issues with a pool of workers, which wait for their results to be consumed.
+// The results are closed once all the issues are enriched
+func (f *JiraFinder) processIssues(in <-chan JiraIssue) chan enrichResult {
+	workers := f.workers()
+	out := make(chan enrichResult, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for issue := range in {
+				err, enriched := f.enrichIssue(issue)
+				if err == nil {
+					err = f.runProcessors(enriched)
+				}
+				if err != nil {
+					err = errors.Wrapf(err, "error while processing issue %s", issue.Key())
+				}
+
+				out <- enrichResult{issue.Key(), enriched, err}
 			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
 
-			subTasks := parent["fields"].(map[string]interface{})["subtasks"].([]interface{})
-			result := make([]SubTask, 0)
+	return out
+}
+
+// Processor post-processes an enriched issue, its errors are handled like the enrichment ones
+type Processor interface {
+	Process(issue *JiraIssue) error
+}
 
-			for _, v := range subTasks {
-				_, subTaskIssue := f.getIssue(v.(map[string]interface{})["id"].(string), false)
-				assignee := getValueFromField(subTaskIssue, "assignee")
-				issueType := getValueFromField(subTaskIssue, "issuetype")
-				name := getValueFromField(subTaskIssue, "summary")
-				totalHours := getValueFromField(subTaskIssue, "timetracking")
-				currentSubTask := SubTask{TaskType: issueType, Name: name, AssigneeName: assignee, TotalHours: totalHours}
+func (f *JiraFinder) runProcessors(issue *JiraIssue) error {
+	for _, p := range f.Processors {
+		if err := p.Process(issue); err != nil {
+			return errors.Wrapf(err, "failed to post-process")
+		}
+	}
+
+	return nil
+}
 
-				result = append(result, currentSubTask)
+// workers gives the number of concurrent requests, the configured Workers or 10 by default
+func (f *JiraFinder) workers() int {
+	if f.Config.Workers <= 0 {
+		return defaultWorkers
+	}
+
+	return f.Config.Workers
+}
+
+// enrichIssues enriches all the issues, it fails on the first error unless ContinueOnError is set
+// in which case the errors are collected in Errors by issue key
+func (f *JiraFinder) enrichIssues(issues []JiraIssue) (error, []JiraIssue) {
+	in := make(chan JiraIssue)
+	go func() {
+		for _, issue := range issues {
+			in <- issue
+		}
+		close(in)
+	}()
+
+	return f.enrichStream(in)
+}
+
+// enrichStream enriches the issues of the channel as they arrive, until it is closed, like enrichIssues
+func (f *JiraFinder) enrichStream(issues <-chan JiraIssue) (error, []JiraIssue) {
+	var firstErr error
+	enriched := make([]JiraIssue, 0)
+
+	for r := range f.processIssues(issues) {
+		if r.err == nil {
+			enriched = append(enriched, *r.issue)
+			continue
+		}
+
+		if !f.Config.ContinueOnError {
+			if firstErr == nil {
+				firstErr = r.err
 			}
+			continue
+		}
+
+		log.Println(r.err)
+		if f.Errors == nil {
+			f.Errors = make(map[string]error)
+		}
+		f.Errors[r.key] = r.err
+	}
 
-			issue.SubTasks = result
+	if firstErr != nil {
+		return firstErr, nil
+	}
+
+	return nil, enriched
+}
+
+// enrichIssue fills the sub tasks of the issue, and the developer for bugs
+func (f *JiraFinder) enrichIssue(issue JiraIssue) (error, *JiraIssue) {
+	issueID := issue.Data["id"].(string)
+	err, parent := f.getIssue(issueID, true)
+	if err != nil {
+		return err, nil
+	}
 
-			parentIssueType := getValueFromField(parent, "issuetype")
-			if isBug(parentIssueType) {
-				issue.AssigneeName = getDeveloperNameFromLog(parent)
+	parentKey, _ := parent["key"].(string)
+	subTasks := parent["fields"].(map[string]interface{})["subtasks"].([]interface{})
+	result := make([]SubTask, 0)
+
+	for _, v := range subTasks {
+		err, subTaskIssue := f.getSubTask(v.(map[string]interface{})["id"].(string))
+		if err != nil {
+			// a sub task which can't be retrieved, e.g. without permission, doesn't fail its parent
+			result = append(result, SubTask{ParentKey: parentKey, FetchError: err})
+			if httprequest.IsNotFound(err) {
+				// moved, deleted or in an archived project
+				issue.Stale = true
 			}
-			out <- &issue
-		}(issue, i)
+			continue
+		}
+
+		assignee := getValueFromField(subTaskIssue, "assignee")
+		issueType := getValueFromField(subTaskIssue, "issuetype")
+		name := getValueFromField(subTaskIssue, "summary")
+		totalHours := getTimeTracking(subTaskIssue, f.Config.TimeTrackingField)
+		currentSubTask := SubTask{TaskType: issueType, Name: name, AssigneeName: assignee, TotalHours: totalHours, ParentKey: parentKey}
+		currentSubTask.OriginalEstimateSeconds = JiraIssue{Data: subTaskIssue}.OriginalEstimateSeconds()
+		currentSubTask.TimeSpentSeconds = JiraIssue{Data: subTaskIssue}.TimeSpentSeconds()
+		currentSubTask.Status = JiraIssue{Data: subTaskIssue}.Status()
+
+		result = append(result, currentSubTask)
 	}
 
-	return out
+	issue.SubTasks = result
+
+	if f.Config.SprintField != "" {
+		issue.CurrentSprint = currentSprint(JiraIssue{Data: parent}.Sprints(f.Config.SprintField))
+	}
+
+	if f.Config.IncludeRemoteLinks {
+		if err, issue.RemoteLinks = f.GetRemoteLinks(issueID); err != nil {
+			return err, nil
+		}
+	}
+
+	if f.Config.IncludeTransitions {
+		issue.Transitions = parseTransitions(parent)
+	}
+
+	if f.Config.IncludeComponentDetails {
+		if err, issue.Components = f.issueComponents(parent); err != nil {
+			return err, nil
+		}
+	}
+
+	parentIssueType := getValueFromField(parent, "issuetype")
+	if isBug(parentIssueType) {
+		issue.AssigneeName = f.getDeveloperName(parent)
+	}
+
+	return nil, &issue
+}
+
+// getDeveloperName reads the developer from the configured developer field, falling back to the changelog
+func (f *JiraFinder) getDeveloperName(issue map[string]interface{}) string {
+	if f.Config.DeveloperField != "" {
+		if name := getUserFromField(issue, f.Config.DeveloperField); name != "" {
+			return name
+		}
+	}
+
+	return getDeveloperNameFromLog(issue)
+}
+
+// get sends the request to jira, the raw response being logged in verbose mode
+func (f *JiraFinder) get(path string, params map[string]string) (error, []byte) {
+	err, body := f.api.Get(path, params)
+	if f.Config.Verbose {
+		log.Printf("GET %s %v: %s", path, params, body)
+	}
+
+	return err, body
+}
+
+// post sends the json body to jira, the raw response being logged in verbose mode
+func (f *JiraFinder) post(path string, body []byte) (error, []byte) {
+	err, response := f.api.Post(path, body)
+	if f.Config.Verbose {
+		log.Printf("POST %s %s: %s", path, body, response)
+	}
+
+	return err, response
+}
+
+// GetIssueRaw gives the unparsed json of the issue, to troubleshoot the fields mapping
+func (f *JiraFinder) GetIssueRaw(issueID string) (error, json.RawMessage) {
+	err, body := f.getRawIssue(issueID, f.issueParams(false))
+	if err != nil {
+		return err, nil
+	}
+
+	return nil, json.RawMessage(body)
+}
+
+// GetIssueByKey retrieves the issue by its key. The key of an issue changes when it is moved to another project,
+// the old keys being resolved by jira on some instances only, else through the jql which matches the historical keys
+func (f *JiraFinder) GetIssueByKey(key string) (error, JiraIssue) {
+	err, data := f.getIssueWithParams(key, f.issueParams(false))
+	if err != nil {
+		if !httprequest.IsNotFound(err) {
+			return err, JiraIssue{}
+		}
+
+		searchErr, moved := f.searchMovedIssue(key)
+		if searchErr != nil || len(moved) == 0 {
+			return err, JiraIssue{}
+		}
+		data = moved[0].Data
+	}
+
+	issue := JiraIssue{Data: data, Fields: f.fieldKeys}
+	if current := issue.Key(); current != "" && !strings.EqualFold(current, key) {
+		issue.NewKey = current
+	}
+
+	return nil, issue
+}
+
+// searchMovedIssue searches the issue by a key it had before being moved
+func (f *JiraFinder) searchMovedIssue(key string) (error, []JiraIssue) {
+	err, result := f.search("issuekey = "+quoteJql(key), f.fieldKeys)
+	if err != nil {
+		return err, nil
+	}
+
+	return nil, f.prepareIssueObjects(result, f.fieldKeys)
 }
 
 func (f *JiraFinder) getIssue(issueID string, includeChangeLog bool) (error, map[string]interface{}) {
-	var responseResult map[string]interface{}
-	var getIssueURL string
+	return f.getIssueWithParams(issueID, f.issueParams(includeChangeLog))
+}
 
-	getIssueURL = "/rest/api/2/issue/" + issueID
+// subTaskFields are the only fields read from the sub tasks
+var subTaskFields = []string{"summary", "assignee", "issuetype", "status", "timetracking", "timeoriginalestimate", "timespent"}
 
-	if includeChangeLog {
-		getIssueURL += "?expand=changelog"
+// getSubTask gets the sub task, with only the fields read from it when MinimalSubTasks is set
+func (f *JiraFinder) getSubTask(issueID string) (error, map[string]interface{}) {
+	if !f.Config.MinimalSubTasks {
+		return f.getIssue(issueID, false)
 	}
 
-	body := f.api.Get(getIssueURL, nil)
+	return f.getIssueWithParams(issueID, map[string]string{"fields": strings.Join(subTaskFields, ",")})
+}
+
+func (f *JiraFinder) getIssueWithParams(issueID string, params map[string]string) (error, map[string]interface{}) {
+	var responseResult map[string]interface{}
+
+	err, body := f.getRawIssue(issueID, params)
+	if err != nil {
+		return err, nil
+	}
 
 	if err := json.Unmarshal(body, &responseResult); err != nil {
 		return errors.Wrapf(err, "failed to retrieve issue"), responseResult
@@ -325,16 +1216,176 @@ func (f *JiraFinder) getIssue(issueID string, includeChangeLog bool) (error, map
 	return nil, responseResult
 }
 
-func download(issue JiraIssue) []string {
+// issueParams gives the params of the issue request, expanding the changelog and the configured Expand
+func (f *JiraFinder) issueParams(includeChangeLog bool) map[string]string {
+	expand := make([]string, 0, len(f.Config.Expand)+2)
+	if includeChangeLog {
+		expand = append(expand, "changelog")
+	}
+	expand = append(expand, f.Config.Expand...)
+	if f.Config.IncludeTransitions && !contains(expand, "transitions") {
+		expand = append(expand, "transitions")
+	}
+
+	if len(expand) > 0 {
+		return map[string]string{"expand": strings.Join(expand, ",")}
+	}
+
+	return nil
+}
+
+func (f *JiraFinder) getRawIssue(issueID string, params map[string]string) (error, []byte) {
+	err, body := f.get(f.apiPath("/issue/"+issueID), params)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve issue %s", issueID), nil
+	}
+
+	return nil, body
+}
+
+// GetIssueProperty gives the raw json value of the issue property, as stored by the apps
+func (f *JiraFinder) GetIssueProperty(issueID string, propertyKey string) (error, json.RawMessage) {
+	var property struct {
+		Key   string          `json:"key"`
+		Value json.RawMessage `json:"value"`
+	}
+
+	err, body := f.get(f.apiPath("/issue/"+issueID+"/properties/"+propertyKey), nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve property %s of issue %s", propertyKey, issueID), nil
+	}
+
+	if err := json.Unmarshal(body, &property); err != nil {
+		return errors.Wrapf(err, "failed to parse property %s of issue %s", propertyKey, issueID), nil
+	}
+
+	return nil, property.Value
+}
+
+// RemoteLink is a link from an issue to an external resource, like a confluence page or a pull request
+type RemoteLink struct {
+	Title        string
+	URL          string
+	Relationship string
+}
+
+// GetRemoteLinks gives the remote links of the issue
+func (f *JiraFinder) GetRemoteLinks(issueID string) (error, []RemoteLink) {
+	var response []struct {
+		Relationship string `json:"relationship"`
+		Object       struct {
+			URL   string `json:"url"`
+			Title string `json:"title"`
+		} `json:"object"`
+	}
+
+	err, body := f.get(f.apiPath("/issue/"+issueID+"/remotelink"), nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve remote links of issue %s", issueID), nil
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return errors.Wrapf(err, "failed to parse remote links of issue %s", issueID), nil
+	}
+
+	links := make([]RemoteLink, 0, len(response))
+	for _, l := range response {
+		links = append(links, RemoteLink{Title: l.Object.Title, URL: l.Object.URL, Relationship: l.Relationship})
+	}
+
+	return nil, links
+}
+
+// commentsPageSize is the number of comments asked per page
+const commentsPageSize = 100
+
+// GetComments gives all the comments of the issue, page by page. With a limit above 0
+// only the latest comments up to the limit are given, newest first
+func (f *JiraFinder) GetComments(issueID string, limit int) (error, []Comment) {
+	comments := make([]Comment, 0)
+	params := map[string]string{"maxResults": strconv.Itoa(commentsPageSize)}
+	if limit > 0 {
+		params["orderBy"] = "-created"
+	}
+
+	for {
+		var page struct {
+			StartAt  int                      `json:"startAt"`
+			Total    int                      `json:"total"`
+			Comments []map[string]interface{} `json:"comments"`
+		}
+
+		params["startAt"] = strconv.Itoa(len(comments))
+		err, body := f.get(f.apiPath("/issue/"+issueID+"/comment"), params)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve comments of issue %s", issueID), nil
+		}
+
+		if err := json.Unmarshal(body, &page); err != nil {
+			return errors.Wrapf(err, "failed to parse comments of issue %s", issueID), nil
+		}
+
+		for _, c := range page.Comments {
+			comments = append(comments, parseComment(c))
+		}
+
+		if limit > 0 && len(comments) >= limit {
+			return nil, comments[:limit]
+		}
+
+		if len(page.Comments) == 0 || len(comments) >= page.Total {
+			return nil, comments
+		}
+	}
+}
+
+func download(issue JiraIssue, c config.Configuration) []string {
 	fieldValues := make([]string, 0)
 
 	// Listen to final populated issue and prepare the output for all the fields mentioned in the configuration
 	for _, field := range issue.Fields {
+		var value string
 		val, ok := issue.Data[field]
 		if ok {
-			fieldValues = append(fieldValues, strings.Replace(val.(string), ",", "", -1))
+			value = val.(string)
+		} else if field == "phase" {
+			value = issue.Phase(c.StatusPhaseMap)
+		} else if field == "flagged" && c.FlaggedField != "" {
+			value = strconv.FormatBool(issue.IsFlagged(c.FlaggedField))
+		} else if candidates, ok := c.FieldFallbacks[field]; ok {
+			value = fallbackValue(issue, candidates)
+		} else if field == "storypoints" {
+			if points, ok := issue.StoryPoints(c.StoryPointsField); ok {
+				value = strconv.FormatFloat(points, 'f', -1, 64)
+			} else {
+				value = "N/A"
+			}
+		} else if strings.ToLower(field) == "created" && c.TimeZone != "" {
+			value = getDateFromField(issue.Data, field, c.TimeZone)
 		} else {
-			fieldValues = append(fieldValues, getFieldValue(field, issue))
+			value = getFieldValue(field, issue)
+		}
+
+		if value == "N/A" && c.EmptyValue != nil {
+			value = *c.EmptyValue
+		}
+
+		if c.NormalizeWhitespace {
+			value = strings.Join(strings.Fields(value), " ")
+		}
+
+		// commas are quoted by the csv writer, stripping them is only kept for backward compatibility
+		if c.StripCommas {
+			value = strings.Replace(value, ",", "", -1)
+		}
+
+		if max, ok := c.MaxFieldLength[field]; ok {
+			value = truncate(value, max)
+		}
+
+		fieldValues = append(fieldValues, value)
+		if c.RawColumns {
+			fieldValues = append(fieldValues, rawValue(issue, field))
 		}
 	}
 	if len(fieldValues) > 0 {
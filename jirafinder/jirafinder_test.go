@@ -1,11 +1,56 @@
 package jirafinder
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/gojira/ferry/config"
+	"github.com/gojira/ferry/httprequest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
+// newTestFinder gives a finder talking to a test server using the given handler
+func newTestFinder(t *testing.T, handler http.HandlerFunc) *JiraFinder {
+	api := httptest.NewServer(handler)
+	t.Cleanup(api.Close)
+
+	err, f := NewJiraFinderFomFile("../example_config/sample_for_test.json")
+	require.NoErrorf(t, err, "instantiation resulting to error: '%s'", err)
+
+	f.Config.JiraURL = api.URL
+	f.api = httprequest.NewClient(api.URL, f.Config.AuthToken)
+
+	return f
+}
+
+// serveIssues answers the issue requests with the json of the issue id, 404 for unknown ones
+func serveIssues(issues map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := strings.TrimPrefix(req.URL.Path, "/rest/api/2/issue/")
+		body, ok := issues[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errorMessages": ["Issue does not exist or you do not have permission to see it."], "errors": {}}`)
+			return
+		}
+
+		fmt.Fprint(w, body)
+	}
+}
+
 func TestJiraFinder_DownloadIssue(t *testing.T) {
 	r := assert.New(t)
 
@@ -22,11 +67,90 @@ func TestJiraFinder_DownloadIssue(t *testing.T) {
 		Fields: []string{"key", "summary", "assignee"},
 	}
 
-	row := download(issue)
+	row := download(issue, config.Configuration{})
 	expectedValue := []string{"POS-7", "Fix issue", "N/A"}
 	r.EqualValues(expectedValue, row, "Wrong result")
 }
 
+func TestJiraFinder_DownloadIssueEmptyValue(t *testing.T) {
+	r := assert.New(t)
+
+	issue := JiraIssue{
+		Data: map[string]interface{}{
+			"key":    "POS-7",
+			"fields": map[string]interface{}{"summary": "Fix issue"},
+		},
+		Fields: []string{"key", "summary", "assignee"},
+	}
+
+	empty := ""
+	r.EqualValues([]string{"POS-7", "Fix issue", ""}, download(issue, config.Configuration{EmptyValue: &empty}), "Wrong result")
+	r.EqualValues([]string{"POS-7", "Fix issue", "N/A"}, download(issue, config.Configuration{}), "Wrong result")
+}
+
+func TestJiraFinder_DownloadIssueKeepsCommas(t *testing.T) {
+	r := assert.New(t)
+
+	issue := JiraIssue{
+		Data: map[string]interface{}{
+			"key": "POS-7",
+			"fields": map[string]interface{}{
+				"summary": "Fix issue, then release",
+			},
+		},
+		Fields: []string{"key", "summary"},
+	}
+
+	r.EqualValues([]string{"POS-7", "Fix issue, then release"}, download(issue, config.Configuration{}), "Wrong result")
+	r.EqualValues([]string{"POS-7", "Fix issue then release"}, download(issue, config.Configuration{StripCommas: true}), "Wrong result")
+}
+
+func TestJiraFinder_DownloadIssueNormalizeWhitespace(t *testing.T) {
+	r := assert.New(t)
+
+	issue := JiraIssue{
+		Data: map[string]interface{}{
+			"key":    "POS-7",
+			"fields": map[string]interface{}{"summary": "  Fix\tthe totals\r\n  of the\n\nreport "},
+		},
+		Fields: []string{"key", "summary"},
+	}
+
+	r.EqualValues([]string{"POS-7", "Fix the totals of the report"}, download(issue, config.Configuration{NormalizeWhitespace: true}), "Wrong result")
+	r.EqualValues([]string{"POS-7", "  Fix\tthe totals\r\n  of the\n\nreport "}, download(issue, config.Configuration{}), "expected raw summary by default")
+}
+
+func TestJiraFinder_DownloadIssueTruncated(t *testing.T) {
+	r := assert.New(t)
+
+	issue := JiraIssue{
+		Data: map[string]interface{}{
+			"key":    "POS-7",
+			"fields": map[string]interface{}{"summary": "Corriger l'écran du tableau de bord"},
+		},
+		Fields: []string{"key", "summary"},
+	}
+
+	row := download(issue, config.Configuration{MaxFieldLength: map[string]int{"summary": 12}})
+	r.EqualValues([]string{"POS-7", "Corriger l'…"}, row, "Wrong result")
+}
+
+func TestJiraFinder_DownloadIssueRawColumns(t *testing.T) {
+	r := assert.New(t)
+
+	issue := JiraIssue{
+		Data: map[string]interface{}{
+			"key":    "POS-7",
+			"fields": map[string]interface{}{"customfield_10030": map[string]interface{}{"id": "10100", "child": "Critical"}},
+		},
+		Fields: []string{"customfield_10030"},
+	}
+
+	c := config.Configuration{FieldsToRetrieve: []string{"severity"}, RawColumns: true}
+	r.EqualValues([]string{"severity", "severity_raw"}, header(c.FieldsToRetrieve, c.RawColumns), "Wrong header")
+	r.EqualValues([]string{"", `{"child":"Critical","id":"10100"}`}, download(issue, c), "Wrong result")
+}
+
 func TestJiraFinder_DownloadIssueEmpty(t *testing.T) {
 	r := assert.New(t)
 	issue := JiraIssue{
@@ -37,7 +161,7 @@ func TestJiraFinder_DownloadIssueEmpty(t *testing.T) {
 		Fields: []string{},
 	}
 
-	row := download(issue)
+	row := download(issue, config.Configuration{})
 	r.EqualValues([]string{}, row, "Expected empty row")
 }
 
@@ -53,6 +177,62 @@ func TestJiraFinder_NewFinder(t *testing.T) {
 	r.EqualValues("https://your-jira-url.com", f.Config.JiraURL, "wrong jira endpoint")
 }
 
+func TestJiraFinder_NumericFilters(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	r.NoError(os.WriteFile(path, []byte(`JiraUrl: https://your-jira-url.com
+Filters:
+  Project: POS
+  Sprint: 42
+  FixVersion: 1.5
+FieldsToRetrieve:
+  - key
+`), 0644))
+
+	err, f := NewJiraFinderFomFile(path)
+	r.NoErrorf(err, "instantiation resulting to error: '%s'", err)
+
+	filters, _ := f.processFields([]map[string]interface{}{
+		{"id": "project", "name": "Project", "custom": false},
+		{"id": "customfield_10020", "name": "Sprint", "custom": true},
+		{"id": "fixVersions", "name": "FixVersion", "custom": false},
+	})
+	r.Equal(map[string]string{"Project": "POS", "cf[10020]": "42", "FixVersion": "1.5"}, filters, "wrong numeric filters")
+
+	err, _ = NewJiraFinder(&config.Configuration{JiraURL: "https://your-jira-url.com", Filters: map[string]interface{}{"Sprint": []interface{}{1, 2}}})
+	r.Error(err, "expected error for a list filter")
+	r.Contains(err.Error(), "invalid filter 'Sprint'")
+}
+
+func TestJiraFinder_NewFinderInvalidTimeTracking(t *testing.T) {
+	r := require.New(t)
+
+	err, _ := NewJiraFinder(&config.Configuration{JiraURL: "https://your-jira-url.com", TimeTrackingField: "estimate"})
+	r.Errorf(err, "expected instantiation to fail")
+	r.Containsf(err.Error(), "invalid TimeTrackingField", "expected 'invalid TimeTrackingField', got '%s'", err)
+}
+
+func TestJiraFinder_NewFinderInvalidTimeZone(t *testing.T) {
+	r := require.New(t)
+
+	err, _ := NewJiraFinder(&config.Configuration{JiraURL: "https://your-jira-url.com", TimeZone: "Mars/Olympus"})
+	r.Errorf(err, "expected instantiation to fail")
+	r.Containsf(err.Error(), "invalid TimeZone", "expected 'invalid TimeZone', got '%s'", err)
+}
+
+func TestJiraFinder_NewFinderDeadline(t *testing.T) {
+	r := require.New(t)
+
+	err, f := NewJiraFinder(&config.Configuration{JiraURL: "https://your-jira-url.com", Deadline: "90s"})
+	r.NoErrorf(err, "instantiation resulting to error: '%s'", err)
+	r.Equal(90*time.Second, f.api.Deadline, "wrong deadline")
+
+	err, _ = NewJiraFinder(&config.Configuration{JiraURL: "https://your-jira-url.com", Deadline: "soon"})
+	r.Errorf(err, "expected instantiation to fail")
+	r.Containsf(err.Error(), "invalid Deadline", "expected 'invalid Deadline', got '%s'", err)
+}
+
 func TestJiraFinder_Search(t *testing.T) {
 	r := require.New(t)
 	err, f := NewJiraFinderFomFile("../example_config/sample_for_test.json")
@@ -64,3 +244,1086 @@ func TestJiraFinder_Search(t *testing.T) {
 	err = f.Search()
 	r.NoErrorf(err, "search func resulting to error: %s", err)
 }
+
+func TestJiraFinder_SearchProgress(t *testing.T) {
+	r := require.New(t)
+	total := 250
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		startAt, _ := strconv.Atoi(req.URL.Query().Get("startAt"))
+		issues := "["
+		for i := startAt; i < total && i < startAt+100; i++ {
+			if i > startAt {
+				issues += ","
+			}
+			issues += fmt.Sprintf(`{"id": "%d"}`, i)
+		}
+		issues += "]"
+		fmt.Fprintf(w, `{"startAt": %d, "maxResults": 100, "total": %d, "issues": %s}`, startAt, total, issues)
+	})
+
+	calls := make([][2]int, 0)
+	f.Progress = func(fetched, total int) {
+		calls = append(calls, [2]int{fetched, total})
+	}
+
+	err, result := f.search("", []string{})
+	r.NoErrorf(err, "search func resulting to error: %s", err)
+	r.Len(result.Issues, total, "wrong number of issues")
+	r.EqualValues([][2]int{{100, total}, {200, total}, {250, total}}, calls, "wrong progress calls")
+}
+
+func TestJiraFinder_GetFilterJQL(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/filter/10042", req.URL.Path, "wrong filter path")
+		fmt.Fprint(w, `{"id": "10042", "name": "Open bugs", "jql": "project = POS AND issuetype = Bug"}`)
+	})
+
+	err, jql := f.GetFilterJQL("10042")
+	r.NoErrorf(err, "GetFilterJQL resulting to error: %s", err)
+	r.Equal("project = POS AND issuetype = Bug", jql, "wrong filter jql")
+}
+
+func TestJiraFinder_SearchExpandNamesAndRenderedFields(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("names,renderedFields", req.URL.Query().Get("expand"), "wrong expand param")
+		fmt.Fprint(w, `{
+  "startAt": 0, "maxResults": 100, "total": 1,
+  "names": {"summary": "Summary", "customfield_10016": "Story Points"},
+  "issues": [{
+    "id": "10006",
+    "key": "POS-7",
+    "fields": {"summary": "Fix issue", "description": "Some *bold* text"},
+    "renderedFields": {"description": "<p>Some <b>bold</b> text</p>"}
+  }]
+}`)
+	})
+	f.Config.Expand = []string{"names", "renderedFields"}
+
+	err, result := f.search("", []string{"summary"})
+	r.NoErrorf(err, "search func resulting to error: %s", err)
+
+	issues := f.prepareIssueObjects(result, []string{"summary"})
+	r.Len(issues, 1, "wrong number of issues")
+	r.Equal("Story Points", issues[0].Names["customfield_10016"], "wrong field display name")
+	r.Equal("<p>Some <b>bold</b> text</p>", issues[0].RenderedField("description"), "wrong rendered value")
+	r.Equal("", issues[0].RenderedField("summary"), "expected empty rendered value")
+}
+
+func TestJiraFinder_SearchTokenPagination(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/3/search/jql", req.URL.Path, "wrong search path, expected the configured api path")
+		a.Empty(req.URL.Query().Get("startAt"), "startAt should not be sent")
+
+		switch req.URL.Query().Get("nextPageToken") {
+		case "":
+			fmt.Fprint(w, `{"issues": [{"id": "1"}, {"id": "2"}], "nextPageToken": "page-2"}`)
+		case "page-2":
+			fmt.Fprint(w, `{"issues": [{"id": "3"}], "isLast": true}`)
+		default:
+			t.Errorf("unexpected token %s", req.URL.Query().Get("nextPageToken"))
+		}
+	})
+	f.Config.TokenPagination = true
+	f.Config.APIPath = "/rest/api/3"
+
+	err, result := f.search("project = POS", []string{})
+	r.NoErrorf(err, "search func resulting to error: %s", err)
+	r.Len(result.Issues, 3, "wrong number of issues")
+	r.Equal(3, result.Total, "wrong total")
+}
+
+func TestJiraFinder_DeveloperNameFromField(t *testing.T) {
+	r := assert.New(t)
+
+	issue := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"customfield_10050": map[string]interface{}{"displayName": "Field Dev"},
+		},
+		"changelog": map[string]interface{}{
+			"histories": []interface{}{
+				map[string]interface{}{
+					"author": map[string]interface{}{"displayName": "Log Dev"},
+					"items":  []interface{}{map[string]interface{}{"toString": "In Development"}},
+				},
+			},
+		},
+	}
+
+	f := &JiraFinder{Config: config.Configuration{DeveloperField: "customfield_10050"}}
+	r.Equal("Field Dev", f.getDeveloperName(issue), "expected developer from field")
+
+	issue["fields"].(map[string]interface{})["customfield_10050"] = nil
+	r.Equal("Log Dev", f.getDeveloperName(issue), "expected developer from changelog")
+
+	f.Config.DeveloperField = ""
+	r.Equal("Log Dev", f.getDeveloperName(issue), "expected developer from changelog")
+}
+
+func TestJiraFinder_SubTasksParentKey(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, serveIssues(map[string]string{
+		"10006": `{"id": "10006", "key": "POS-7", "fields": {"issuetype": {"name": "Story"}, "subtasks": [{"id": "10017"}, {"id": "10018"}]}}`,
+		"10017": `{"id": "10017", "key": "POS-18", "fields": {"summary": "Dev : Coding", "issuetype": {"name": "Sub-task"}}}`,
+		"10018": `{"id": "10018", "key": "POS-19", "fields": {"summary": "QA : Testing", "issuetype": {"name": "Sub-task"}}}`,
+	}))
+
+	err, issue := f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	r.NoErrorf(err, "enrichIssue resulting to error: %s", err)
+	r.Len(issue.SubTasks, 2, "wrong number of sub tasks")
+	for _, subTask := range issue.SubTasks {
+		r.Equal("POS-7", subTask.ParentKey, "wrong parent key for %s", subTask.Name)
+	}
+}
+
+func TestJiraFinder_EnrichIssuesErrors(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, serveIssues(map[string]string{
+		"10001": `{"id": "10001", "key": "POS-1", "fields": {"issuetype": {"name": "Story"}, "subtasks": []}}`,
+		"10003": `{"id": "10003", "key": "POS-3", "fields": {"issuetype": {"name": "Story"}, "subtasks": []}}`,
+	}))
+
+	issues := []JiraIssue{
+		{Data: map[string]interface{}{"id": "10001", "key": "POS-1"}},
+		{Data: map[string]interface{}{"id": "10002", "key": "POS-2"}},
+		{Data: map[string]interface{}{"id": "10003", "key": "POS-3"}},
+	}
+
+	err, _ := f.enrichIssues(issues)
+	r.Errorf(err, "expected enrichIssues to fail fast")
+	r.Contains(err.Error(), "POS-2", "expected error of the missing issue")
+
+	f.Config.ContinueOnError = true
+	err, enriched := f.enrichIssues(issues)
+	r.NoErrorf(err, "enrichIssues resulting to error: %s", err)
+	r.Len(enriched, 2, "expected the other issues to succeed")
+	r.Len(f.Errors, 1, "expected one collected error")
+	r.Contains(f.Errors["POS-2"].Error(), "404", "expected not found error for POS-2")
+}
+
+func TestJiraFinder_SearchWarnings(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("warn", req.URL.Query().Get("validateQuery"), "expected validateQuery param")
+		if req.URL.Query().Get("startAt") == "0" {
+			fmt.Fprint(w, `{"startAt": 0, "maxResults": 1, "total": 2, "issues": [{"id": "1", "key": "POS-1"}],
+  "warningMessages": ["The value 'Secret' does not exist for the field 'securitylevel'."]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt": 1, "maxResults": 1, "total": 2, "issues": [{"id": "2", "key": "POS-2"}],
+  "warningMessages": ["The value 'Secret' does not exist for the field 'securitylevel'."]}`)
+	})
+	f.Config.SearchParams = map[string]string{"validateQuery": "warn"}
+
+	err, result := f.search(`project = POS AND level = Secret`, []string{})
+	r.NoErrorf(err, "search resulting to error: %s", err)
+	r.Len(result.Issues, 2, "expected the issues despite the warnings")
+	r.Equal([]string{"The value 'Secret' does not exist for the field 'securitylevel'."}, result.WarningMessages, "wrong warnings")
+}
+
+type ageProcessor struct {
+	now time.Time
+}
+
+func (p ageProcessor) Process(issue *JiraIssue) error {
+	created, ok := issue.field("created").(string)
+	if !ok {
+		return fmt.Errorf("no created date")
+	}
+
+	date, err := time.Parse(jiraTimeLayout, created)
+	if err != nil {
+		return err
+	}
+
+	issue.Data["age"] = strconv.Itoa(int(p.now.Sub(date).Hours() / 24))
+	return nil
+}
+
+func TestJiraFinder_Processors(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, serveIssues(map[string]string{
+		"10001": `{"id": "10001", "key": "POS-1", "fields": {"issuetype": {"name": "Story"}, "subtasks": []}}`,
+		"10002": `{"id": "10002", "key": "POS-2", "fields": {"issuetype": {"name": "Story"}, "subtasks": []}}`,
+	}))
+	f.Processors = []Processor{ageProcessor{now: time.Date(2020, 8, 20, 0, 0, 0, 0, time.UTC)}}
+	f.Config.ContinueOnError = true
+
+	issues := []JiraIssue{
+		{Data: map[string]interface{}{"id": "10001", "key": "POS-1", "fields": map[string]interface{}{"created": "2020-08-17T08:13:32.383+0000"}}, Fields: []string{"key", "age"}},
+		{Data: map[string]interface{}{"id": "10002", "key": "POS-2", "fields": map[string]interface{}{}}},
+	}
+
+	err, enriched := f.enrichIssues(issues)
+	r.NoErrorf(err, "enrichIssues resulting to error: %s", err)
+	r.Len(enriched, 1, "expected the issue failing its processor to be skipped")
+	r.Equal([]string{"POS-1", "2"}, download(enriched[0], f.Config), "expected the derived field to be exported")
+	r.Contains(f.Errors["POS-2"].Error(), "no created date", "expected the processor error to be collected")
+}
+
+func TestJiraFinder_GetIssueProperty(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/issue/10006/properties/support.checklist", req.URL.Path, "wrong property path")
+		fmt.Fprint(w, `{"key": "support.checklist", "value": {"items": [{"name": "review", "done": true}]}}`)
+	})
+
+	err, value := f.GetIssueProperty("10006", "support.checklist")
+	r.NoErrorf(err, "GetIssueProperty resulting to error: %s", err)
+	r.JSONEq(`{"items": [{"name": "review", "done": true}]}`, string(value), "wrong property value")
+
+	var checklist struct {
+		Items []struct {
+			Name string `json:"name"`
+			Done bool   `json:"done"`
+		} `json:"items"`
+	}
+	r.NoError(json.Unmarshal(value, &checklist), "property value should decode")
+	r.True(checklist.Items[0].Done, "wrong decoded property")
+}
+
+func TestJiraFinder_GetIssueRaw(t *testing.T) {
+	r := require.New(t)
+
+	body := `{"key": "POS-7", "fields": {"customfield_10026": {"value": null}, "summary": "Fix issue"}}`
+	f := newTestFinder(t, serveIssues(map[string]string{"POS-7": body}))
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	f.Config.Verbose = true
+
+	err, raw := f.GetIssueRaw("POS-7")
+	r.NoErrorf(err, "GetIssueRaw resulting to error: %s", err)
+	r.Equal(body, string(raw), "raw issue should be the server body")
+	r.Contains(logs.String(), body, "expected raw body to be logged in verbose mode")
+
+	err, _ = f.GetIssueRaw("POS-8")
+	r.Error(err, "expected error for unknown issue")
+}
+
+func TestJiraFinder_CacheDir(t *testing.T) {
+	r := require.New(t)
+
+	calls := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"id": "10006", "key": "POS-7", "fields": {"summary": "Fix issue"}}`)
+	}))
+	defer api.Close()
+
+	c := &config.Configuration{JiraURL: api.URL, CacheDir: t.TempDir(), CacheTTL: "1h"}
+	for i := 0; i < 2; i++ {
+		err, f := NewJiraFinder(c)
+		r.NoError(err)
+
+		err, raw := f.GetIssueRaw("10006")
+		r.NoError(err)
+		r.Contains(string(raw), "POS-7")
+	}
+	r.Equal(1, calls, "expected the second finder to read the cache")
+
+	c.CacheTTL = "one hour"
+	err, _ := NewJiraFinder(c)
+	r.Error(err, "expected invalid ttl error")
+}
+
+func TestJiraFinder_ExtractIssueKeys(t *testing.T) {
+	r := require.New(t)
+
+	commits := `commit 3f2a1c9
+    POS-7 fix the rounding of the totals
+
+    Follows up on POS-12 and OPS2-3, reverted in pos-8.
+
+commit 9b8e7d6
+    Merge POS-7 and API-42 into release-1.2
+`
+
+	err, f := NewJiraFinder(&config.Configuration{JiraURL: "https://your-jira-url.com"})
+	r.NoError(err)
+	r.Equal([]string{"POS-7", "POS-12", "OPS2-3", "API-42"}, f.ExtractIssueKeys(commits), "wrong keys")
+
+	err, f = NewJiraFinder(&config.Configuration{JiraURL: "https://your-jira-url.com", IssueKeyPattern: `(?i)\bPOS-\d+`})
+	r.NoError(err)
+	r.Equal([]string{"POS-7", "POS-12", "pos-8"}, f.ExtractIssueKeys(commits), "wrong keys of the configured pattern")
+
+	err, _ = NewJiraFinder(&config.Configuration{JiraURL: "https://your-jira-url.com", IssueKeyPattern: `POS-(\d+`})
+	r.Error(err, "expected invalid pattern error")
+	r.Contains(err.Error(), "invalid IssueKeyPattern")
+}
+
+func TestJiraFinder_GetIssueByKeyMoved(t *testing.T) {
+	r := require.New(t)
+
+	issues := serveIssues(map[string]string{"NEW-3": `{"id": "10006", "key": "NEW-3", "fields": {"summary": "Fix issue"}}`})
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/rest/api/2/search" {
+			issues(w, req)
+			return
+		}
+
+		if req.URL.Query().Get("jql") != `issuekey = "POS-7"` {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errorMessages": ["An issue with key 'POS-8' does not exist for field 'issuekey'."]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt": 0, "maxResults": 100, "total": 1, "issues": [{"id": "10006", "key": "NEW-3", "fields": {"summary": "Fix issue"}}]}`)
+	})
+
+	err, issue := f.GetIssueByKey("NEW-3")
+	r.NoError(err)
+	r.Equal("", issue.NewKey, "expected no new key for the current key")
+
+	err, issue = f.GetIssueByKey("POS-7")
+	r.NoError(err)
+	r.Equal("NEW-3", issue.Key(), "wrong issue key")
+	r.Equal("NEW-3", issue.NewKey, "expected the new key of the moved issue")
+
+	err, _ = f.GetIssueByKey("POS-8")
+	r.True(httprequest.IsNotFound(err), "expected not found error for unknown key, got %v", err)
+}
+
+func TestJiraFinder_GetComments(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/issue/POS-7/comment", req.URL.Path, "wrong comments path")
+
+		startAt := req.URL.Query().Get("startAt")
+		order := req.URL.Query().Get("orderBy")
+		switch {
+		case startAt == "0" && order == "":
+			fmt.Fprint(w, `{"startAt": 0, "maxResults": 2, "total": 3, "comments": [{"body": "first"}, {"body": "second"}]}`)
+		case startAt == "2" && order == "":
+			fmt.Fprint(w, `{"startAt": 2, "maxResults": 2, "total": 3, "comments": [{"body": "third"}]}`)
+		case startAt == "0" && order == "-created":
+			fmt.Fprint(w, `{"startAt": 0, "maxResults": 2, "total": 3, "comments": [{"body": "third"}, {"body": "second"}]}`)
+		default:
+			t.Errorf("unexpected comments request %s", req.URL.RawQuery)
+		}
+	})
+
+	err, comments := f.GetComments("POS-7", 0)
+	r.NoErrorf(err, "GetComments resulting to error: %s", err)
+	r.Len(comments, 3, "expected the comments of both pages")
+	r.Equal("first", comments[0].Body, "wrong first comment")
+	r.Equal("third", comments[2].Body, "wrong last comment")
+
+	err, comments = f.GetComments("POS-7", 1)
+	r.NoErrorf(err, "GetComments resulting to error: %s", err)
+	r.Len(comments, 1, "expected limited comments")
+	r.Equal("third", comments[0].Body, "expected the latest comment")
+}
+
+func TestJiraFinder_SearchExtraParams(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		a.Equal("names", query.Get("expand"), "expected extra expand param")
+		a.Equal("warn", query.Get("validateQuery"), "expected extra validateQuery param")
+		a.Equal("project = POS", query.Get("jql"), "reserved jql param should not be overridden")
+		a.Equal("0", query.Get("startAt"), "reserved startAt param should not be overridden")
+		fmt.Fprint(w, `{"startAt": 0, "maxResults": 100, "total": 0, "issues": []}`)
+	})
+	f.Config.SearchParams = map[string]string{"expand": "names", "validateQuery": "warn", "jql": "project = OTHER", "startAt": "50"}
+
+	err, _ := f.search("project = POS", []string{})
+	r.NoErrorf(err, "search func resulting to error: %s", err)
+}
+
+func TestJiraFinder_GetRemoteLinks(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/rest/api/2/issue/10006/remotelink":
+			fmt.Fprint(w, `[
+  {"id": 10000, "relationship": "mentioned in", "object": {"url": "https://wiki.example.com/pages/42", "title": "Design"}},
+  {"id": 10001, "relationship": "fixed by", "object": {"url": "https://git.example.com/pulls/7", "title": "PR #7"}}
+]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	})
+
+	err, links := f.GetRemoteLinks("10006")
+	r.NoErrorf(err, "GetRemoteLinks resulting to error: %s", err)
+	r.Equal([]RemoteLink{
+		{Title: "Design", URL: "https://wiki.example.com/pages/42", Relationship: "mentioned in"},
+		{Title: "PR #7", URL: "https://git.example.com/pulls/7", Relationship: "fixed by"},
+	}, links, "wrong remote links")
+
+	err, links = f.GetRemoteLinks("10007")
+	r.NoErrorf(err, "GetRemoteLinks resulting to error: %s", err)
+	r.Empty(links, "expected no remote links")
+}
+
+func TestJiraFinder_IncludeTransitions(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/issue/10006", req.URL.Path, "wrong issue path")
+		a.Equal("changelog,renderedFields,transitions", req.URL.Query().Get("expand"), "wrong expand param")
+		fmt.Fprint(w, `{
+  "id": "10006",
+  "key": "POS-7",
+  "fields": {"issuetype": {"name": "Story"}, "subtasks": []},
+  "transitions": [
+    {"id": "21", "name": "Start Progress", "to": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}}},
+    {"id": "31", "name": "Resolve", "to": {"name": "Done", "statusCategory": {"key": "done"}}}
+  ]
+}`)
+	})
+	f.Config.Expand = []string{"renderedFields", "transitions"}
+	f.Config.IncludeTransitions = true
+
+	err, issue := f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	r.NoErrorf(err, "enrichIssue resulting to error: %s", err)
+	r.Equal([]Transition{
+		{ID: "21", Name: "Start Progress", To: Status{Name: "In Progress", CategoryKey: "indeterminate"}},
+		{ID: "31", Name: "Resolve", To: Status{Name: "Done", CategoryKey: "done"}},
+	}, issue.Transitions, "wrong transitions")
+}
+
+func TestJiraFinder_SubTaskFetchError(t *testing.T) {
+	r := require.New(t)
+
+	issues := serveIssues(map[string]string{
+		"10006": `{"id": "10006", "key": "POS-7", "fields": {"issuetype": {"name": "Story"}, "subtasks": [{"id": "10017"}, {"id": "10018"}, {"id": "10019"}]}}`,
+		"10017": `{"id": "10017", "key": "POS-18", "fields": {"summary": "Dev : Coding"}}`,
+		"10019": `{"id": "10019", "key": "POS-20", "fields": {"summary": "QA : Testing"}}`,
+	})
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/rest/api/2/issue/10018" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		issues(w, req)
+	})
+
+	err, issue := f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	r.NoErrorf(err, "enrichIssue resulting to error: %s", err)
+	r.Len(issue.SubTasks, 3, "wrong number of sub tasks")
+
+	r.NoError(issue.SubTasks[0].FetchError, "unexpected error for first sub task")
+	r.Equal("Dev : Coding", issue.SubTasks[0].Name, "wrong first sub task")
+	r.Error(issue.SubTasks[1].FetchError, "expected error for forbidden sub task")
+	r.Contains(issue.SubTasks[1].FetchError.Error(), "403", "expected forbidden error")
+	r.Equal("POS-7", issue.SubTasks[1].ParentKey, "wrong parent key of failed sub task")
+	r.Equal("QA : Testing", issue.SubTasks[2].Name, "wrong last sub task")
+	r.False(issue.Stale, "forbidden sub task should not flag its parent as stale")
+}
+
+func TestJiraFinder_EnrichCurrentSprint(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, serveIssues(map[string]string{
+		"10006": `{"id": "10006", "key": "POS-7", "fields": {"issuetype": {"name": "Story"}, "subtasks": [],
+  "customfield_10020": [{"id": 1, "name": "POS Sprint 1", "state": "closed"}, {"id": 2, "name": "POS Sprint 2", "state": "active"}]}}`,
+	}))
+	f.Config.SprintField = "customfield_10020"
+
+	err, issue := f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	r.NoErrorf(err, "enrichIssue resulting to error: %s", err)
+	r.NotNil(issue.CurrentSprint, "expected a current sprint")
+	r.Equal("POS Sprint 2", issue.CurrentSprint.Name, "wrong current sprint")
+	r.Equal("active", issue.CurrentSprint.State, "wrong current sprint state")
+}
+
+func TestJiraFinder_MinimalSubTasks(t *testing.T) {
+	r := require.New(t)
+
+	queries := make(map[string]url.Values)
+	issues := serveIssues(map[string]string{
+		"10006": `{"id": "10006", "key": "POS-7", "fields": {"issuetype": {"name": "Story"}, "subtasks": [{"id": "10017"}]}}`,
+		"10017": `{"id": "10017", "key": "POS-18", "fields": {"summary": "Dev : Coding"}}`,
+	})
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		queries[req.URL.Path] = req.URL.Query()
+		issues(w, req)
+	})
+	f.Config.Expand = []string{"renderedFields"}
+	f.Config.MinimalSubTasks = true
+
+	err, issue := f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	r.NoErrorf(err, "enrichIssue resulting to error: %s", err)
+	r.Equal("Dev : Coding", issue.SubTasks[0].Name, "wrong sub task")
+
+	subTask := queries["/rest/api/2/issue/10017"]
+	r.Equal("summary,assignee,issuetype,status,timetracking,timeoriginalestimate,timespent", subTask.Get("fields"), "expected only the sub task fields")
+	r.Empty(subTask.Get("expand"), "expected no expand for sub tasks")
+	r.Equal("changelog,renderedFields", queries["/rest/api/2/issue/10006"].Get("expand"), "parent should keep its expand")
+}
+
+func TestJiraFinder_StaleIssue(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, serveIssues(map[string]string{
+		"10006": `{"id": "10006", "key": "POS-7", "fields": {"issuetype": {"name": "Story"}, "subtasks": [{"id": "10017"}, {"id": "10018"}]}}`,
+		"10017": `{"id": "10017", "key": "POS-18", "fields": {"summary": "Dev : Coding"}}`,
+	}))
+
+	err, issue := f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	r.NoErrorf(err, "enrichIssue resulting to error: %s", err)
+	r.True(issue.Stale, "expected parent of a deleted sub task to be stale")
+	r.Len(issue.SubTasks, 2, "wrong number of sub tasks")
+	r.True(httprequest.IsNotFound(issue.SubTasks[1].FetchError), "expected not found error for deleted sub task")
+}
+
+func TestJiraFinder_NormalizeFields(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/field", req.URL.Path, "wrong fields path")
+		fmt.Fprint(w, `[
+  {"id": "summary", "name": "Summary", "custom": false},
+  {"id": "status", "name": "Status", "custom": false},
+  {"id": "customfield_10026", "name": "Story Points", "custom": true},
+  {"id": "customfield_10100", "name": "Developer", "custom": true}
+]`)
+	})
+
+	err, ids, labels := f.NormalizeFields([]string{"Summary", "customfield_10026", "developer", "status"})
+	r.NoErrorf(err, "NormalizeFields resulting to error: %s", err)
+	r.Equal("summary,customfield_10026,customfield_10100,status", strings.Join(ids, ","), "wrong fields param")
+	r.Equal(map[string]string{"summary": "Summary", "customfield_10026": "customfield_10026", "customfield_10100": "developer", "status": "status"}, labels, "wrong labels")
+
+	err, _, _ = f.NormalizeFields([]string{"Summary", "Sprint Goal"})
+	r.EqualError(err, "unknown field Sprint Goal", "expected unknown field error")
+}
+
+func TestJiraFinder_SearchUnknownField(t *testing.T) {
+	r := require.New(t)
+
+	searches := make(chan url.Values, 1)
+	f := newTestFinder(t, serveSearch(`[{"id": "summary", "name": "Summary", "custom": false}]`, `{"total": 0, "issues": []}`, searches))
+	f.Config.Filters = nil
+	f.Config.FieldsToRetrieve = []string{"summary", "Sprint Goal"}
+
+	err := f.Search()
+	r.Errorf(err, "expected the unknown field to fail the search")
+	r.Contains(err.Error(), "unknown field Sprint Goal", "wrong unknown field error")
+	r.Empty(searches, "expected no search with an unknown field")
+}
+
+func TestJiraFinder_SearchStorySample(t *testing.T) {
+	r := require.New(t)
+
+	err, f := NewJiraFinderFomFile("../example_config/sample_config_story_search.json")
+	r.NoErrorf(err, "instantiation resulting to error: '%s'", err)
+	f.UseStub()
+
+	rows := searchCsv(t, f)
+	r.Equal([]string{"key", "summary", "assignee", "scrum team", "story points", "complexity", "bug count"}, rows[0], "wrong header")
+	r.Greater(len(rows), 1, "expected the issues of the stub")
+	r.Equal([]string{"Extra Small", "0"}, rows[1][5:], "expected the complexity and bug count to be computed")
+}
+
+func TestJiraFinder_FieldAliases(t *testing.T) {
+	r := require.New(t)
+
+	fields := []map[string]interface{}{
+		{"id": "summary", "name": "Summary", "custom": false},
+		{"id": "customfield_10016", "name": "Story point estimate", "custom": true},
+		{"id": "customfield_10020", "name": "Team", "custom": true},
+	}
+
+	err, f := NewJiraFinder(&config.Configuration{
+		JiraURL:          "https://your-jira-url.com",
+		FieldsToRetrieve: []string{"summary", "story points"},
+		Filters:          map[string]interface{}{"squad": "Payments"},
+		FieldAliases:     map[string]string{"Story Points": "Story point estimate", "squad": "customfield_10020"},
+	})
+	r.NoErrorf(err, "instantiation resulting to error: '%s'", err)
+
+	for i := 0; i < 2; i++ {
+		filters, keys := f.processFields(fields)
+		r.Equal([]string{"summary", "customfield_10016"}, keys, "aliased field should resolve to its id")
+		r.Equal(map[string]string{"cf[10020]": "Payments"}, filters, "aliased filter should resolve to its id")
+	}
+
+	f = newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `[{"id": "summary", "name": "Summary", "custom": false}, {"id": "customfield_10016", "name": "Story point estimate", "custom": true}]`)
+	})
+	f.Config.FieldAliases = map[string]string{"story points": "Story point estimate"}
+
+	err, ids, labels := f.NormalizeFields([]string{"Summary", "story points"})
+	r.NoErrorf(err, "NormalizeFields resulting to error: %s", err)
+	r.Equal([]string{"summary", "customfield_10016"}, ids, "wrong fields param")
+	r.Equal("story points", labels["customfield_10016"], "column should keep the requested name")
+}
+
+func TestJiraFinder_FieldFallbacks(t *testing.T) {
+	r := require.New(t)
+
+	fields := []map[string]interface{}{
+		{"id": "summary", "name": "Summary", "custom": false},
+		{"id": "customfield_10040", "name": "Owner", "custom": true},
+		{"id": "customfield_10052", "name": "Product Owner", "custom": true},
+	}
+
+	c := &config.Configuration{
+		JiraURL:          "https://your-jira-url.com",
+		FieldsToRetrieve: []string{"summary", "owner"},
+		FieldFallbacks:   map[string][]string{"owner": {"customfield_10040", "customfield_10052"}},
+	}
+	err, f := NewJiraFinder(c)
+	r.NoErrorf(err, "instantiation resulting to error: '%s'", err)
+
+	_, keys := f.processFields(fields)
+	r.Equal([]string{"summary", "owner"}, keys, "the fallback field should keep its name")
+
+	params := make(map[string]string)
+	f.setFields(params)
+	r.Equal("summary,customfield_10040,customfield_10052", params["fields"], "expected the candidates to be requested")
+
+	issue := JiraIssue{
+		Data: map[string]interface{}{
+			"key": "POS-7",
+			"fields": map[string]interface{}{
+				"summary":           "Fix issue",
+				"customfield_10040": nil,
+				"customfield_10052": map[string]interface{}{"displayName": "Jane Doe"},
+			},
+		},
+		Fields: keys,
+	}
+	r.Equal([]string{"Fix issue", "Jane Doe"}, download(issue, *c), "expected the second candidate value")
+
+	issue.Data["fields"].(map[string]interface{})["customfield_10040"] = map[string]interface{}{"displayName": "John Roe"}
+	r.Equal([]string{"Fix issue", "John Roe"}, download(issue, *c), "expected the first candidate value")
+
+	delete(issue.Data["fields"].(map[string]interface{}), "customfield_10040")
+	delete(issue.Data["fields"].(map[string]interface{}), "customfield_10052")
+	r.Equal([]string{"Fix issue", "N/A"}, download(issue, *c), "expected N/A without candidate value")
+}
+
+func TestJiraFinder_GetCustomFields(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/field", req.URL.Path, "wrong fields path")
+		fmt.Fprint(w, `[
+  {"id": "summary", "name": "Summary", "custom": false},
+  {"id": "customfield_10016", "name": "Story Points", "custom": true},
+  {"id": "customfield_10020", "name": "Sprint", "custom": true}
+]`)
+	})
+
+	err, customFields := f.GetCustomFields()
+	r.NoErrorf(err, "GetCustomFields resulting to error: %s", err)
+	r.Equal(map[string]string{"Story Points": "customfield_10016", "Sprint": "customfield_10020"}, customFields, "wrong custom fields")
+}
+
+func TestJiraFinder_WarmFieldCache(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	calls := 0
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		a.Equal("/rest/api/2/field", req.URL.Path, "only the fields should be requested")
+		fmt.Fprint(w, `[{"id": "summary", "name": "Summary", "custom": false}, {"id": "customfield_10016", "name": "Story Points", "custom": true}]`)
+	})
+
+	r.NoError(f.WarmFieldCache())
+	r.Equal(1, calls, "expected the fields to be retrieved")
+
+	err, ids, _ := f.NormalizeFields([]string{"Summary", "Story Points"})
+	r.NoErrorf(err, "NormalizeFields resulting to error: %s", err)
+	r.Equal([]string{"summary", "customfield_10016"}, ids, "wrong fields param")
+
+	err, customFields := f.GetCustomFields()
+	r.NoError(err)
+	r.Equal("customfield_10016", customFields["Story Points"], "wrong custom field id")
+	r.Equal(1, calls, "expected no request after warming the cache")
+}
+
+func TestJiraFinder_WarmFieldCacheError(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	r.Error(f.WarmFieldCache(), "expected the fields request error")
+}
+
+func TestJiraFinder_APIPath(t *testing.T) {
+	r := require.New(t)
+
+	paths := make([]string, 0)
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		paths = append(paths, req.URL.Path)
+
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/field"):
+			fmt.Fprint(w, `[]`)
+		case strings.Contains(req.URL.Path, "/filter/"):
+			fmt.Fprint(w, `{"jql": "project = POS"}`)
+		case strings.HasSuffix(req.URL.Path, "/search"):
+			fmt.Fprint(w, `{"startAt": 0, "maxResults": 100, "total": 0, "issues": []}`)
+		case strings.HasSuffix(req.URL.Path, "/remotelink"):
+			fmt.Fprint(w, `[]`)
+		default:
+			fmt.Fprint(w, `{"id": "10006", "key": "POS-7", "fields": {"issuetype": {"name": "Story"}, "subtasks": []}}`)
+		}
+	})
+	f.Config.APIPath = "/jira/rest/api/latest/"
+
+	err, _ := f.produceFields()
+	r.NoError(err)
+	f.GetFilterJQL("10042")
+	f.search("project = POS", []string{})
+	f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	f.GetRemoteLinks("10006")
+	f.GetIssueProperty("10006", "checklist")
+
+	r.Len(paths, 6, "wrong number of requests")
+	for _, path := range paths {
+		r.True(strings.HasPrefix(path, "/jira/rest/api/latest/"), "path %s not built from the configured api path", path)
+	}
+}
+
+func TestJiraFinder_SearchWindowed(t *testing.T) {
+	r := require.New(t)
+
+	queries := make([]string, 0)
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.Query().Get("jql"))
+		// the same issue is updated in every window
+		fmt.Fprintf(w, `{"startAt": 0, "maxResults": 100, "total": 2, "issues": [{"id": "1", "key": "POS-1"}, {"id": "%d", "key": "POS-%d"}]}`, len(queries)+1, len(queries)+1)
+	})
+
+	start := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2020, 8, 25, 0, 0, 0, 0, time.UTC)
+
+	err, issues := f.SearchWindowed("project = POS", start, end, 7*24*time.Hour)
+	r.NoErrorf(err, "SearchWindowed resulting to error: %s", err)
+	r.Len(queries, 4, "wrong number of windowed queries")
+	r.Equal(`(project = POS) AND updated >= "2020/08/01 00:00" AND updated < "2020/08/08 00:00"`, queries[0], "wrong first window")
+	r.Equal(`(project = POS) AND updated >= "2020/08/22 00:00" AND updated < "2020/08/25 00:00"`, queries[3], "wrong last window")
+	r.Len(issues, 5, "expected deduplicated issues")
+}
+
+func TestJiraFinder_SearchMany(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Query().Get("jql") {
+		case "type = Bug AND status = Open":
+			fmt.Fprint(w, `{"startAt": 0, "maxResults": 100, "total": 2, "issues": [{"id": "1", "key": "POS-1"}, {"id": "2", "key": "POS-2"}]}`)
+		case "duedate < now()":
+			fmt.Fprint(w, `{"startAt": 0, "maxResults": 100, "total": 1, "issues": [{"id": "3", "key": "POS-3"}]}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errorMessages": ["Field 'asignee' does not exist."]}`)
+		}
+	})
+	f.Config.Workers = 2
+
+	errs, results := f.SearchMany(map[string]string{
+		"open bugs":  "type = Bug AND status = Open",
+		"overdue":    "duedate < now()",
+		"unassigned": "asignee is EMPTY",
+	})
+
+	r.Len(results, 2, "expected results of the successful queries")
+	r.Len(results["open bugs"], 2, "wrong open bugs")
+	r.Equal("POS-3", results["overdue"][0].Key(), "wrong overdue issue")
+	r.Len(errs, 1, "expected the error of the failed query")
+	r.Contains(errs["unassigned"].Error(), "query unassigned failed", "wrong error")
+}
+
+func TestJiraFinder_SearchByKeys(t *testing.T) {
+	r := require.New(t)
+
+	queries := make([]string, 0)
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		jql := req.URL.Query().Get("jql")
+		queries = append(queries, jql)
+
+		keys := strings.Split(strings.TrimSuffix(strings.TrimPrefix(jql, "key in ("), ")"), ",")
+		issues := make([]string, 0)
+		for _, key := range keys {
+			issues = append(issues, fmt.Sprintf(`{"id": "%s", "key": "%s"}`, strings.TrimPrefix(key, "POS-"), key))
+		}
+		fmt.Fprintf(w, `{"startAt": 0, "maxResults": 100, "total": %d, "issues": [%s]}`, len(issues), strings.Join(issues, ","))
+	})
+	f.Config.MaxJqlLength = 30
+
+	keys := []string{"POS-1", "POS-2", "POS-3", "POS-4", "POS-5", "POS-6", "POS-7"}
+	err, issues := f.SearchByKeys(keys)
+	r.NoErrorf(err, "SearchByKeys resulting to error: %s", err)
+	r.Equal([]string{"key in (POS-1,POS-2,POS-3)", "key in (POS-4,POS-5,POS-6)", "key in (POS-7)"}, queries, "wrong chunks")
+	for _, q := range queries {
+		r.LessOrEqual(len(q), 30, "chunk %s longer than the max length", q)
+	}
+
+	r.Len(issues, 7, "expected the issues of all the chunks")
+	r.Equal("POS-7", issues[6].Key(), "wrong last issue")
+}
+
+func TestJiraFinder_SearchIssueIDs(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/search/id", req.URL.Path, "wrong search path")
+		a.Equal("project = POS", req.URL.Query().Get("jql"), "wrong jql")
+
+		if req.URL.Query().Get("nextPageToken") == "" {
+			fmt.Fprint(w, `{"issueIds": [10001, 10002], "nextPageToken": "page-2"}`)
+			return
+		}
+		a.Equal("page-2", req.URL.Query().Get("nextPageToken"), "wrong page token")
+		fmt.Fprint(w, `{"issueIds": [10003]}`)
+	})
+
+	err, ids := f.SearchIssueIDs("project = POS")
+	r.NoErrorf(err, "SearchIssueIDs resulting to error: %s", err)
+	r.Equal([]string{"10001", "10002", "10003"}, ids, "wrong issue ids")
+}
+
+func TestJiraFinder_EnrichIssuesWorkers(t *testing.T) {
+	r := require.New(t)
+
+	var running, maxRunning int32
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+
+		// wait for the other requests allowed to run concurrently
+		for deadline := time.Now().Add(500 * time.Millisecond); atomic.LoadInt32(&running) < 2 && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		id := strings.TrimPrefix(req.URL.Path, "/rest/api/2/issue/")
+		fmt.Fprintf(w, `{"id": "%s", "key": "POS-%s", "fields": {"issuetype": {"name": "Story"}, "subtasks": []}}`, id, id)
+	})
+	f.Config.Workers = 2
+
+	issues := make([]JiraIssue, 0)
+	for i := 1; i <= 6; i++ {
+		issues = append(issues, JiraIssue{Data: map[string]interface{}{"id": strconv.Itoa(i), "key": "POS-" + strconv.Itoa(i)}})
+	}
+
+	err, enriched := f.enrichIssues(issues)
+	r.NoErrorf(err, "enrichIssues resulting to error: %s", err)
+	r.Len(enriched, 6, "wrong number of enriched issues")
+	r.EqualValues(2, atomic.LoadInt32(&maxRunning), "expected the workers to cap the concurrency")
+}
+
+// searchCsv runs the search of the finder and gives the rows of the written csv
+func searchCsv(t *testing.T, f *JiraFinder) [][]string {
+	f.Config.DownloadPath = filepath.Join(t.TempDir(), "issues.csv")
+	err := f.Search()
+	require.NoErrorf(t, err, "search func resulting to error: %s", err)
+
+	file, err := os.Open(f.Config.DownloadPath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	return rows
+}
+
+// serveSearch answers the fields and search requests with the given json, the issues being enriched without
+// sub tasks, and sends the params of the search requests to the searches
+func serveSearch(fields string, search string, searches chan<- url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/rest/api/2/field":
+			fmt.Fprint(w, fields)
+		case "/rest/api/2/search":
+			searches <- req.URL.Query()
+			fmt.Fprint(w, search)
+		default:
+			id := strings.TrimPrefix(req.URL.Path, "/rest/api/2/issue/")
+			fmt.Fprintf(w, `{"id": "%s", "key": "POS-%s", "fields": {"issuetype": {"name": "Story"}, "subtasks": []}}`, id, id)
+		}
+	}
+}
+
+func TestJiraFinder_SearchPhase(t *testing.T) {
+	r := require.New(t)
+
+	searches := make(chan url.Values, 1)
+	f := newTestFinder(t, serveSearch(
+		`[{"id": "summary", "name": "Summary", "custom": false}, {"id": "status", "name": "Status", "custom": false}]`,
+		`{"total": 1, "issues": [{"id": "1", "key": "POS-1", "fields": {"summary": "First", "status": {"name": "In Development"}}}]}`,
+		searches,
+	))
+	f.Config.Filters = nil
+	f.Config.FieldsToRetrieve = []string{"Summary", "phase"}
+	f.Config.StatusPhaseMap = map[string]string{"In Development": "In Progress"}
+
+	rows := searchCsv(t, f)
+	r.Equal("summary,status", (<-searches).Get("fields"), "expected the id of the summary and the status of the phase to be requested")
+	r.Equal([][]string{{"Summary", "phase"}, {"First", "In Progress"}}, rows, "wrong rows")
+}
+
+func TestJiraFinder_SearchFlagged(t *testing.T) {
+	r := require.New(t)
+
+	searches := make(chan url.Values, 1)
+	f := newTestFinder(t, serveSearch(
+		`[{"id": "issuekey", "name": "Key", "custom": false}, {"id": "customfield_10021", "name": "Flagged", "custom": true}]`,
+		`{"total": 2, "issues": [
+  {"id": "1", "key": "POS-1", "fields": {"customfield_10021": [{"value": "Impediment"}]}},
+  {"id": "2", "key": "POS-2", "fields": {"customfield_10021": null}}
+]}`,
+		searches,
+	))
+	f.Config.Filters = nil
+	f.Config.FieldsToRetrieve = []string{"key", "flagged"}
+	f.Config.FlaggedField = "customfield_10021"
+
+	rows := searchCsv(t, f)
+	r.Equal("customfield_10021", (<-searches).Get("fields"), "expected the flagged field to be requested")
+	r.ElementsMatch([][]string{{"POS-1", "true"}, {"POS-2", "false"}}, rows[1:], "wrong rows")
+}
+
+func TestJiraFinder_SearchStoryPoints(t *testing.T) {
+	r := require.New(t)
+
+	searches := make(chan url.Values, 1)
+	f := newTestFinder(t, serveSearch(
+		`[
+  {"id": "issuekey", "name": "Key", "custom": false},
+  {"id": "customfield_10026", "name": "Story Points", "custom": true},
+  {"id": "customfield_10050", "name": "Estimate", "custom": true}
+]`,
+		`{"total": 3, "issues": [
+  {"id": "1", "key": "POS-1", "fields": {"customfield_10050": 3, "customfield_10026": null}},
+  {"id": "2", "key": "POS-2", "fields": {"customfield_10050": null, "customfield_10026": 2.5}},
+  {"id": "3", "key": "POS-3", "fields": {"customfield_10050": null, "customfield_10026": null}}
+]}`,
+		searches,
+	))
+	f.Config.Filters = nil
+	f.Config.FieldsToRetrieve = []string{"key", "storypoints"}
+	f.Config.StoryPointsField = "Estimate"
+
+	rows := searchCsv(t, f)
+	r.Equal("customfield_10050,customfield_10026", (<-searches).Get("fields"), "expected the story points fields to be requested")
+	r.ElementsMatch([][]string{{"POS-1", "3"}, {"POS-2", "2.5"}, {"POS-3", "N/A"}}, rows[1:], "wrong rows")
+}
+
+func TestJiraFinder_SearchAnonymizedRawColumns(t *testing.T) {
+	r := require.New(t)
+
+	searches := make(chan url.Values, 1)
+	f := newTestFinder(t, serveSearch(
+		`[{"id": "issuekey", "name": "Key", "custom": false}, {"id": "reporter", "name": "Reporter", "custom": false}]`,
+		`{"total": 1, "issues": [{"id": "1", "key": "POS-1", "fields": {"reporter": {"displayName": "Jane Doe", "emailAddress": "jane@example.com"}}}]}`,
+		searches,
+	))
+	f.Config.Filters = nil
+	f.Config.FieldsToRetrieve = []string{"key", "reporter"}
+	f.Config.AnonymizeFields = []string{"reporter"}
+	f.Config.RawColumns = true
+
+	rows := searchCsv(t, f)
+	<-searches
+	r.Equal([][]string{{"key", "key_raw", "reporter", "reporter_raw"}, {"POS-1", `"POS-1"`, "User 1", ""}}, rows, "expected the raw reporter to be blanked")
+}
+
+func TestJiraFinder_SearchEnrichesPagesAsTheyArrive(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	var enriching int32
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/rest/api/2/field":
+			fmt.Fprint(w, `[{"id": "issuekey", "name": "Key", "custom": false}, {"id": "project", "name": "Project", "custom": false}, {"id": "summary", "name": "Summary", "custom": false}]`)
+		case req.URL.Path == "/rest/api/2/search" && req.URL.Query().Get("startAt") == "0":
+			fmt.Fprint(w, `{"total": 2, "issues": [{"id": "1", "key": "POS-1", "fields": {"summary": "First"}}]}`)
+		case req.URL.Path == "/rest/api/2/search":
+			// the first page is enriched while the last one is requested
+			for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&enriching) == 0 && time.Now().Before(deadline); {
+				time.Sleep(time.Millisecond)
+			}
+			a.EqualValues(1, atomic.LoadInt32(&enriching), "expected the first page to be enriched before the last one is fetched")
+			fmt.Fprint(w, `{"total": 2, "issues": [{"id": "2", "key": "POS-2", "fields": {"summary": "Second"}}]}`)
+		default:
+			atomic.StoreInt32(&enriching, 1)
+			id := strings.TrimPrefix(req.URL.Path, "/rest/api/2/issue/")
+			fmt.Fprintf(w, `{"id": "%s", "key": "POS-%s", "fields": {"issuetype": {"name": "Story"}, "subtasks": []}}`, id, id)
+		}
+	})
+	f.Config.Workers = 1
+	f.Config.Filters = map[string]interface{}{"Project": "POS"}
+	f.Config.FieldsToRetrieve = []string{"key", "summary"}
+
+	rows := searchCsv(t, f)
+	r.Len(rows, 3, "expected the header and the issues of both pages")
+	r.ElementsMatch([][]string{{"POS-1", "First"}, {"POS-2", "Second"}}, rows[1:], "wrong rows")
+}
+
+func TestJiraFinder_SubTasksTimeSeconds(t *testing.T) {
+	r := require.New(t)
+
+	f := newTestFinder(t, serveIssues(map[string]string{
+		"10006": `{"id": "10006", "key": "POS-7", "fields": {"issuetype": {"name": "Story"}, "subtasks": [{"id": "10017"}, {"id": "10018"}]}}`,
+		"10017": `{"id": "10017", "key": "POS-18", "fields": {"timeoriginalestimate": 14400, "timespent": 3600, "status": {"name": "Done", "statusCategory": {"key": "done"}}}}`,
+		"10018": `{"id": "10018", "key": "POS-19", "fields": {"timeoriginalestimate": null, "timespent": null}}`,
+	}))
+
+	err, issue := f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	r.NoErrorf(err, "enrichIssue resulting to error: %s", err)
+	r.EqualValues(14400, issue.SubTasks[0].OriginalEstimateSeconds, "wrong sub task original estimate")
+	r.EqualValues(3600, issue.SubTasks[0].TimeSpentSeconds, "wrong sub task time spent")
+	r.EqualValues(0, issue.SubTasks[1].OriginalEstimateSeconds, "expected no sub task original estimate")
+	r.Equal("done", issue.SubTasks[0].Status.CategoryKey, "wrong sub task status")
+}
+
+func TestJiraFinder_SearchFieldsByKeys(t *testing.T) {
+	r := require.New(t)
+
+	var fieldsByKeys []string
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		fieldsByKeys = append(fieldsByKeys, req.URL.Query().Get("fieldsByKeys"))
+		fmt.Fprint(w, `{"startAt": 0, "maxResults": 100, "total": 0, "issues": []}`)
+	})
+
+	f.search("project = POS", []string{})
+	f.Config.FieldsByKeys = true
+	f.search("project = POS", []string{})
+
+	r.Equal([]string{"", "true"}, fieldsByKeys, "expected fieldsByKeys only when enabled")
+}
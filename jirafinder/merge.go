@@ -0,0 +1,117 @@
+package jirafinder
+
+// MergeIssue merges two partial payloads of the same issue, e.g. a search result with the minimal fields and a later
+// full fetch. The values of the more complete issue, the one with the most fields set, are kept and its missing
+// values taken from the other one
+func MergeIssue(a JiraIssue, b JiraIssue) JiraIssue {
+	if setFieldsCount(b) > setFieldsCount(a) {
+		a, b = b, a
+	}
+
+	merged := a
+	merged.Data = mergeData(a.Data, b.Data)
+	merged.Fields = mergeStrings(a.Fields, b.Fields)
+
+	if len(merged.SubTasks) == 0 {
+		merged.SubTasks = b.SubTasks
+	}
+
+	merged.RemoteLinks = append([]RemoteLink{}, a.RemoteLinks...)
+	for _, link := range b.RemoteLinks {
+		if !containsLink(merged.RemoteLinks, link) {
+			merged.RemoteLinks = append(merged.RemoteLinks, link)
+		}
+	}
+
+	if len(a.Names)+len(b.Names) > 0 {
+		merged.Names = make(map[string]string)
+		for _, names := range []map[string]string{b.Names, a.Names} {
+			for id, name := range names {
+				merged.Names[id] = name
+			}
+		}
+	}
+
+	if merged.AssigneeName == "" {
+		merged.AssigneeName = b.AssigneeName
+	}
+	if merged.CurrentSprint == nil {
+		merged.CurrentSprint = b.CurrentSprint
+	}
+	if merged.NewKey == "" {
+		merged.NewKey = b.NewKey
+	}
+	merged.Stale = a.Stale || b.Stale
+
+	return merged
+}
+
+// setFieldsCount gives the number of non null fields of the issue
+func setFieldsCount(issue JiraIssue) int {
+	fields, _ := issue.Data["fields"].(map[string]interface{})
+
+	count := 0
+	for _, val := range fields {
+		if val != nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+// mergeData gives the values of the primary payload, the missing or null ones being taken from the other payload,
+// the nested objects like the fields being merged the same way
+func mergeData(primary map[string]interface{}, other map[string]interface{}) map[string]interface{} {
+	if primary == nil && other == nil {
+		return nil
+	}
+
+	merged := make(map[string]interface{}, len(primary))
+	for key, val := range primary {
+		merged[key] = val
+	}
+
+	for key, val := range other {
+		current, ok := merged[key]
+		if !ok || current == nil {
+			merged[key] = val
+			continue
+		}
+
+		currentObj, ok := current.(map[string]interface{})
+		if otherObj, isObj := val.(map[string]interface{}); ok && isObj {
+			merged[key] = mergeData(currentObj, otherObj)
+		}
+	}
+
+	return merged
+}
+
+// mergeStrings gives the values of both lists in their order without duplicates
+func mergeStrings(a []string, b []string) []string {
+	if a == nil && b == nil {
+		return nil
+	}
+
+	merged := make([]string, 0, len(a)+len(b))
+	for _, values := range [][]string{a, b} {
+		for _, v := range values {
+			if !contains(merged, v) {
+				merged = append(merged, v)
+			}
+		}
+	}
+
+	return merged
+}
+
+func containsLink(links []RemoteLink, link RemoteLink) bool {
+	for _, l := range links {
+		if l.URL == link.URL {
+			return true
+		}
+	}
+
+	return false
+}
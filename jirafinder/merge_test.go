@@ -0,0 +1,49 @@
+package jirafinder
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMergeIssue(t *testing.T) {
+	r := require.New(t)
+
+	stub := decodeIssue(t, `{
+  "id": "10006",
+  "key": "POS-7",
+  "fields": {"summary": "Fix issue", "customfield_10016": 3, "assignee": null}
+}`)
+	stub.Fields = []string{"summary", "customfield_10016"}
+	stub.RemoteLinks = []RemoteLink{{Title: "PR #12", URL: "https://github.com/org/repo/pull/12"}}
+
+	full := decodeIssue(t, `{
+  "id": "10006",
+  "key": "POS-7",
+  "fields": {
+    "summary": "Fix issue",
+    "assignee": {"displayName": "Jane Doe"},
+    "status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}},
+    "subtasks": [{"id": "10007"}]
+  },
+  "changelog": {"histories": []}
+}`)
+	full.Fields = []string{"summary", "assignee", "status"}
+	full.SubTasks = []SubTask{{Name: "Write tests", ParentKey: "POS-7"}}
+	full.RemoteLinks = []RemoteLink{
+		{Title: "PR #12", URL: "https://github.com/org/repo/pull/12"},
+		{Title: "Design", URL: "https://wiki.example.com/design"},
+	}
+
+	for _, merged := range []JiraIssue{MergeIssue(stub, full), MergeIssue(full, stub)} {
+		r.Equal("POS-7", merged.Key(), "wrong key")
+		r.Equal("Jane Doe", getUserFromField(merged.Data, "assignee"), "expected the assignee of the full fetch")
+		r.Equal("In Progress", merged.Status().Name, "expected the status of the full fetch")
+		r.Equal(3.0, merged.field("customfield_10016"), "expected the story points of the search")
+		r.NotNil(merged.Data["changelog"], "expected the changelog of the full fetch")
+		r.Len(merged.SubTasks, 1, "expected the sub tasks of the full fetch")
+		r.Len(merged.RemoteLinks, 2, "expected the links of both without duplicates")
+		r.ElementsMatch([]string{"summary", "customfield_10016", "assignee", "status"}, merged.Fields, "wrong fields")
+	}
+
+	r.Nil(stub.field("status"), "the merged issues should not be modified")
+}
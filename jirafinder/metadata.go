@@ -0,0 +1,226 @@
+package jirafinder
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+)
+
+// IssueType is a type of issue, the hierarchy level being -1 for sub tasks, 0 for standard issues and 1 for epics
+type IssueType struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchyLevel"`
+}
+
+// ListIssueTypes gives the issue types of the jira instance
+func (f *JiraFinder) ListIssueTypes() (error, []IssueType) {
+	var types []IssueType
+
+	err, body := f.get(f.apiPath("/issuetype"), nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve issue types"), nil
+	}
+
+	if err := json.Unmarshal(body, &types); err != nil {
+		return errors.Wrapf(err, "failed to parse issue types"), nil
+	}
+
+	return nil, types
+}
+
+// CreateField is a field of the create issue screen of a project and issue type
+type CreateField struct {
+	ID            string
+	Name          string
+	Required      bool
+	AllowedValues []string
+}
+
+// GetCreateMeta gives the fields to set when creating an issue of the type in the project, sorted by id
+func (f *JiraFinder) GetCreateMeta(projectKey string, issueType string) (error, []CreateField) {
+	var meta struct {
+		Projects []struct {
+			IssueTypes []struct {
+				Fields map[string]struct {
+					Name          string                   `json:"name"`
+					Required      bool                     `json:"required"`
+					AllowedValues []map[string]interface{} `json:"allowedValues"`
+				} `json:"fields"`
+			} `json:"issuetypes"`
+		} `json:"projects"`
+	}
+
+	params := map[string]string{
+		"projectKeys":    projectKey,
+		"issuetypeNames": issueType,
+		"expand":         "projects.issuetypes.fields",
+	}
+
+	err, body := f.get(f.apiPath("/issue/createmeta"), params)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve create meta of %s %s", projectKey, issueType), nil
+	}
+
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return errors.Wrapf(err, "failed to parse create meta of %s %s", projectKey, issueType), nil
+	}
+
+	fields := make([]CreateField, 0)
+	for _, project := range meta.Projects {
+		for _, it := range project.IssueTypes {
+			for id, field := range it.Fields {
+				createField := CreateField{ID: id, Name: field.Name, Required: field.Required}
+				for _, allowed := range field.AllowedValues {
+					createField.AllowedValues = append(createField.AllowedValues, allowedValueName(allowed))
+				}
+
+				fields = append(fields, createField)
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].ID < fields[j].ID
+	})
+
+	return nil, fields
+}
+
+// allowedValueName gives the label of an allowed value: options have a value, most other objects a name
+func allowedValueName(allowed map[string]interface{}) string {
+	for _, key := range []string{"value", "name", "key", "id"} {
+		if val := nestedString(allowed, key); val != "" {
+			return val
+		}
+	}
+
+	return ""
+}
+
+// DoneStatuses gives the names of the statuses in the done category, the configured DoneStatuses when set
+func (f *JiraFinder) DoneStatuses() (error, []string) {
+	if len(f.Config.DoneStatuses) > 0 {
+		return nil, f.Config.DoneStatuses
+	}
+
+	var statuses []map[string]interface{}
+
+	err, body := f.get(f.apiPath("/status"), nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve statuses"), nil
+	}
+
+	if err := json.Unmarshal(body, &statuses); err != nil {
+		return errors.Wrapf(err, "failed to parse statuses"), nil
+	}
+
+	done := make([]string, 0)
+	for _, s := range statuses {
+		if status := parseStatus(s); status.CategoryKey == "done" {
+			done = append(done, status.Name)
+		}
+	}
+
+	return nil, done
+}
+
+// Component is a component of a project, its issues being assigned by default according to the AssigneeType:
+// "PROJECT_DEFAULT", "COMPONENT_LEAD", "PROJECT_LEAD" or "UNASSIGNED". The Lead is the zero user for the components without lead
+type Component struct {
+	ID           string
+	Name         string
+	Lead         User
+	AssigneeType string
+}
+
+// GetComponent gives the details of the component, retrieved once per finder
+func (f *JiraFinder) GetComponent(componentID string) (error, Component) {
+	f.mu.RLock()
+	component, ok := f.components[componentID]
+	f.mu.RUnlock()
+	if ok {
+		return nil, component
+	}
+
+	var response map[string]interface{}
+
+	err, body := f.get(f.apiPath("/component/"+componentID), nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve component %s", componentID), Component{}
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return errors.Wrapf(err, "failed to parse component %s", componentID), Component{}
+	}
+
+	component = Component{
+		ID:           nestedString(response, "id"),
+		Name:         nestedString(response, "name"),
+		Lead:         parseUser(response["lead"]),
+		AssigneeType: nestedString(response, "assigneeType"),
+	}
+
+	f.mu.Lock()
+	if f.components == nil {
+		f.components = make(map[string]Component)
+	}
+	f.components[componentID] = component
+	f.mu.Unlock()
+
+	return nil, component
+}
+
+// issueComponents gives the details of the components of the issue
+func (f *JiraFinder) issueComponents(issue map[string]interface{}) (error, []Component) {
+	values, _ := JiraIssue{Data: issue}.field("components").([]interface{})
+
+	components := make([]Component, 0, len(values))
+	for _, v := range values {
+		value, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		err, component := f.GetComponent(nestedString(value, "id"))
+		if err != nil {
+			return err, nil
+		}
+		components = append(components, component)
+	}
+
+	return nil, components
+}
+
+// GetMyPermissions tells which of the permissions, like "BROWSE_PROJECTS", the user has in the project, e.g. to skip
+// the projects the user can't see before a long run. BROWSE_PROJECTS is checked when no permission is given
+func (f *JiraFinder) GetMyPermissions(projectKey string, permissions ...string) (error, map[string]bool) {
+	if len(permissions) == 0 {
+		permissions = []string{"BROWSE_PROJECTS"}
+	}
+
+	var response struct {
+		Permissions map[string]struct {
+			HavePermission bool `json:"havePermission"`
+		} `json:"permissions"`
+	}
+
+	params := map[string]string{"projectKey": projectKey, "permissions": strings.Join(permissions, ",")}
+	err, body := f.get(f.apiPath("/mypermissions"), params)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve permissions in project %s", projectKey), nil
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return errors.Wrapf(err, "failed to parse permissions in project %s", projectKey), nil
+	}
+
+	granted := make(map[string]bool, len(response.Permissions))
+	for key, permission := range response.Permissions {
+		granted[key] = permission.HavePermission
+	}
+
+	return nil, granted
+}
@@ -0,0 +1,149 @@
+package jirafinder
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestJiraFinder_GetCreateMeta(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/issue/createmeta", req.URL.Path, "wrong createmeta path")
+		a.Equal("POS", req.URL.Query().Get("projectKeys"), "wrong project")
+		a.Equal("Bug", req.URL.Query().Get("issuetypeNames"), "wrong issue type")
+		fmt.Fprint(w, `{
+  "projects": [{
+    "key": "POS",
+    "issuetypes": [{
+      "name": "Bug",
+      "fields": {
+        "summary": {"required": true, "name": "Summary"},
+        "priority": {"required": false, "name": "Priority", "allowedValues": [{"id": "1", "name": "High"}, {"id": "3", "name": "Medium"}]},
+        "customfield_10030": {"required": true, "name": "Severity", "allowedValues": [{"id": "10100", "value": "Critical"}, {"id": "10101", "value": "Minor"}]}
+      }
+    }]
+  }]
+}`)
+	})
+
+	err, fields := f.GetCreateMeta("POS", "Bug")
+	r.NoErrorf(err, "GetCreateMeta resulting to error: %s", err)
+	r.Equal([]CreateField{
+		{ID: "customfield_10030", Name: "Severity", Required: true, AllowedValues: []string{"Critical", "Minor"}},
+		{ID: "priority", Name: "Priority", Required: false, AllowedValues: []string{"High", "Medium"}},
+		{ID: "summary", Name: "Summary", Required: true},
+	}, fields, "wrong create fields")
+}
+
+func TestJiraFinder_ListIssueTypes(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/issuetype", req.URL.Path, "wrong issue types path")
+		fmt.Fprint(w, `[
+  {"id": "10001", "name": "Story", "subtask": false, "hierarchyLevel": 0},
+  {"id": "10002", "name": "Dev Task", "subtask": true, "hierarchyLevel": -1},
+  {"id": "10003", "name": "Epic", "subtask": false, "hierarchyLevel": 1},
+  {"id": "10004", "name": "Bug", "subtask": false}
+]`)
+	})
+
+	err, types := f.ListIssueTypes()
+	r.NoErrorf(err, "ListIssueTypes resulting to error: %s", err)
+	r.Equal([]IssueType{
+		{ID: "10001", Name: "Story"},
+		{ID: "10002", Name: "Dev Task", Subtask: true, HierarchyLevel: -1},
+		{ID: "10003", Name: "Epic", HierarchyLevel: 1},
+		{ID: "10004", Name: "Bug"},
+	}, types, "wrong issue types")
+}
+
+func TestJiraFinder_DoneStatuses(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/status", req.URL.Path, "wrong statuses path")
+		fmt.Fprint(w, `[
+  {"id": "1", "name": "To Do", "statusCategory": {"key": "new"}},
+  {"id": "3", "name": "In Progress", "statusCategory": {"key": "indeterminate"}},
+  {"id": "10001", "name": "Done", "statusCategory": {"key": "done"}},
+  {"id": "6", "name": "Closed", "statusCategory": {"key": "done"}}
+]`)
+	})
+
+	err, done := f.DoneStatuses()
+	r.NoError(err)
+	r.Equal([]string{"Done", "Closed"}, done, "wrong done statuses")
+
+	f.Config.DoneStatuses = []string{"Released"}
+	err, done = f.DoneStatuses()
+	r.NoError(err)
+	r.Equal([]string{"Released"}, done, "expected the configured done statuses")
+}
+
+func TestJiraFinder_GetComponent(t *testing.T) {
+	r := require.New(t)
+
+	calls := 0
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/rest/api/2/issue/10006":
+			fmt.Fprint(w, `{"id": "10006", "key": "POS-7", "fields": {"issuetype": {"name": "Story"}, "subtasks": [], "components": [{"id": "10000", "name": "Backend"}, {"id": "10001", "name": "Docs"}]}}`)
+		case "/rest/api/2/component/10000":
+			calls++
+			fmt.Fprint(w, `{
+  "id": "10000",
+  "name": "Backend",
+  "lead": {"accountId": "5b10a2844c20165700ede21g", "displayName": "Jane Doe"},
+  "assigneeType": "COMPONENT_LEAD",
+  "realAssigneeType": "COMPONENT_LEAD",
+  "project": "POS"
+}`)
+		case "/rest/api/2/component/10001":
+			fmt.Fprint(w, `{"id": "10001", "name": "Docs", "assigneeType": "PROJECT_DEFAULT", "project": "POS"}`)
+		default:
+			t.Errorf("unexpected path %s", req.URL.Path)
+		}
+	})
+
+	err, component := f.GetComponent("10000")
+	r.NoError(err)
+	r.Equal("Backend", component.Name)
+	r.Equal("Jane Doe", component.Lead.DisplayName)
+	r.Equal("COMPONENT_LEAD", component.AssigneeType)
+
+	f.Config.IncludeComponentDetails = true
+	err, issue := f.enrichIssue(JiraIssue{Data: map[string]interface{}{"id": "10006"}})
+	r.NoError(err)
+	r.Equal([]Component{component, {ID: "10001", Name: "Docs", AssigneeType: "PROJECT_DEFAULT"}}, issue.Components, "wrong components")
+	r.Equal("", issue.Components[1].Lead.String(), "expected no lead")
+	r.Equal(1, calls, "expected the component to be retrieved once")
+}
+
+func TestJiraFinder_GetMyPermissions(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/mypermissions", req.URL.Path, "wrong permissions path")
+		a.Equal("POS", req.URL.Query().Get("projectKey"), "wrong project")
+		a.Equal("BROWSE_PROJECTS,EDIT_ISSUES", req.URL.Query().Get("permissions"), "wrong permissions")
+		fmt.Fprint(w, `{
+  "permissions": {
+    "BROWSE_PROJECTS": {"id": "10", "key": "BROWSE_PROJECTS", "name": "Browse Projects", "type": "PROJECT", "havePermission": true},
+    "EDIT_ISSUES": {"id": "12", "key": "EDIT_ISSUES", "name": "Edit Issues", "type": "PROJECT", "havePermission": false}
+  }
+}`)
+	})
+
+	err, permissions := f.GetMyPermissions("POS", "BROWSE_PROJECTS", "EDIT_ISSUES")
+	r.NoError(err)
+	r.Equal(map[string]bool{"BROWSE_PROJECTS": true, "EDIT_ISSUES": false}, permissions, "wrong permissions")
+}
@@ -0,0 +1,113 @@
+package jirafinder
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sprint is a sprint of the sprint field, its state being "future", "active" or "closed"
+type Sprint struct {
+	ID        int
+	Name      string
+	State     string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// Sprints gives the sprints of the sprint field, which id differs between jira instances.
+// The sprints of the older jira servers, given as strings, are parsed too
+func (i JiraIssue) Sprints(field string) []Sprint {
+	sprints := make([]Sprint, 0)
+
+	list := wrapList(i.field(field))
+	for _, s := range list {
+		if legacy, ok := s.(string); ok {
+			if sprint, ok := parseLegacySprint(legacy); ok {
+				sprints = append(sprints, sprint)
+			}
+			continue
+		}
+
+		sprint, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		id, _ := sprint["id"].(float64)
+		start, _ := time.Parse(time.RFC3339, nestedString(sprint, "startDate"))
+		end, _ := time.Parse(time.RFC3339, nestedString(sprint, "endDate"))
+		sprints = append(sprints, Sprint{
+			ID:        int(id),
+			Name:      nestedString(sprint, "name"),
+			State:     nestedString(sprint, "state"),
+			StartDate: start,
+			EndDate:   end,
+		})
+	}
+
+	return sprints
+}
+
+// legacySprintAttribute starts an attribute of a legacy sprint, the names possibly containing commas
+var legacySprintAttribute = regexp.MustCompile(`^[A-Za-z]+=`)
+
+// parseLegacySprint parses the sprint strings of the older jira servers, like
+// "com.atlassian.greenhopper.service.sprint.Sprint@14b1c359[id=1,rapidViewId=1,state=CLOSED,name=Sprint 1,...]"
+func parseLegacySprint(value string) (Sprint, bool) {
+	start := strings.Index(value, "[")
+	end := strings.LastIndex(value, "]")
+	if start < 0 || end < start {
+		return Sprint{}, false
+	}
+
+	attributes := make(map[string]string)
+	var last string
+	for _, part := range strings.Split(value[start+1:end], ",") {
+		if !legacySprintAttribute.MatchString(part) {
+			if last != "" {
+				attributes[last] += "," + part
+			}
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		last = kv[0]
+		attributes[last] = kv[1]
+	}
+
+	id, err := strconv.Atoi(attributes["id"])
+	if err != nil {
+		return Sprint{}, false
+	}
+
+	startDate, _ := time.Parse(time.RFC3339, attributes["startDate"])
+	endDate, _ := time.Parse(time.RFC3339, attributes["endDate"])
+
+	return Sprint{
+		ID:        id,
+		Name:      attributes["name"],
+		State:     strings.ToLower(attributes["state"]),
+		StartDate: startDate,
+		EndDate:   endDate,
+	}, true
+}
+
+// currentSprint gives the active sprint, else the latest started one, nil without sprint
+func currentSprint(sprints []Sprint) *Sprint {
+	var current *Sprint
+	for i := range sprints {
+		s := &sprints[i]
+		if s.State == "active" {
+			return s
+		}
+
+		if current == nil || s.StartDate.After(current.StartDate) ||
+			(s.StartDate.Equal(current.StartDate) && s.ID > current.ID) {
+			current = s
+		}
+	}
+
+	return current
+}
@@ -0,0 +1,65 @@
+package jirafinder
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+const sprintsIssue = `{"key": "POS-7", "fields": {"customfield_10020": [
+  {"id": 1, "name": "POS Sprint 1", "state": "closed", "startDate": "2020-08-05T09:00:00.000Z", "endDate": "2020-08-19T09:00:00.000Z"},
+  {"id": 3, "name": "POS Sprint 3", "state": "active", "startDate": "2020-09-02T09:00:00.000Z", "endDate": "2020-09-16T09:00:00.000Z"},
+  {"id": 2, "name": "POS Sprint 2", "state": "closed", "startDate": "2020-08-19T09:00:00.000Z", "endDate": "2020-09-02T09:00:00.000Z"}
+]}}`
+
+func TestJiraIssue_Sprints(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, sprintsIssue)
+	sprints := issue.Sprints("customfield_10020")
+	r.Len(sprints, 3, "wrong number of sprints")
+	r.Equal(1, sprints[0].ID, "wrong first sprint id")
+	r.Equal("POS Sprint 1", sprints[0].Name, "wrong first sprint name")
+	r.Equal("closed", sprints[0].State, "wrong first sprint state")
+	r.Equal(time.Date(2020, 8, 5, 9, 0, 0, 0, time.UTC), sprints[0].StartDate, "wrong first sprint start")
+
+	r.Empty(decodeIssue(t, `{"key": "POS-8", "fields": {"customfield_10020": null}}`).Sprints("customfield_10020"), "expected no sprint")
+}
+
+func TestJiraIssue_SingleSprint(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"customfield_10020": {"id": 3, "name": "POS Sprint 3", "state": "active"}}}`)
+	sprints := issue.Sprints("customfield_10020")
+	r.Len(sprints, 1, "expected a single sprint returned as an object")
+	r.Equal("POS Sprint 3", sprints[0].Name, "wrong sprint")
+}
+
+func TestJiraIssue_LegacySprints(t *testing.T) {
+	r := require.New(t)
+
+	issue := decodeIssue(t, `{"key": "POS-7", "fields": {"customfield_10020": [
+  "com.atlassian.greenhopper.service.sprint.Sprint@14b1c359[id=1,rapidViewId=1,state=CLOSED,name=POS Sprint 1,startDate=2020-08-05T09:00:00.000Z,endDate=2020-08-19T09:00:00.000Z,completeDate=2020-08-19T10:00:00.000Z,sequence=1,goal=]",
+  "com.atlassian.greenhopper.service.sprint.Sprint@5e9d2b4a[id=2,rapidViewId=1,state=ACTIVE,name=POS Sprint 2, the last one,startDate=2020-08-19T09:00:00.000Z,endDate=<null>,completeDate=<null>,sequence=2,goal=Release]",
+  "not a sprint"
+]}}`)
+	sprints := issue.Sprints("customfield_10020")
+	r.Len(sprints, 2, "wrong number of legacy sprints")
+	r.Equal(Sprint{ID: 1, Name: "POS Sprint 1", State: "closed", StartDate: time.Date(2020, 8, 5, 9, 0, 0, 0, time.UTC), EndDate: time.Date(2020, 8, 19, 9, 0, 0, 0, time.UTC)}, sprints[0], "wrong first legacy sprint")
+	r.Equal("POS Sprint 2, the last one", sprints[1].Name, "wrong name with a comma")
+	r.Equal("active", sprints[1].State, "wrong second legacy sprint state")
+	r.True(sprints[1].EndDate.IsZero(), "expected no end date")
+	r.Equal("POS Sprint 2, the last one", currentSprint(sprints).Name, "expected the active legacy sprint")
+}
+
+func TestCurrentSprint(t *testing.T) {
+	r := require.New(t)
+
+	sprints := decodeIssue(t, sprintsIssue).Sprints("customfield_10020")
+	r.Equal("POS Sprint 3", currentSprint(sprints).Name, "expected the active sprint")
+
+	closed := []Sprint{sprints[0], sprints[2]}
+	r.Equal("POS Sprint 2", currentSprint(closed).Name, "expected the latest sprint")
+
+	r.Nil(currentSprint(nil), "expected no current sprint")
+}
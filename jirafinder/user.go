@@ -0,0 +1,52 @@
+package jirafinder
+
+import (
+	"net/url"
+	"strings"
+)
+
+// isCloud tells if the instance is a jira cloud one, from the configured Deployment or else the atlassian.net host
+func (f *JiraFinder) isCloud() bool {
+	switch strings.ToLower(f.Config.Deployment) {
+	case "cloud":
+		return true
+	case "server":
+		return false
+	}
+
+	u, err := url.Parse(f.Config.JiraURL)
+	return err == nil && strings.HasSuffix(strings.ToLower(u.Hostname()), ".atlassian.net")
+}
+
+// UserIdentifier gives the identifier of the user in the jql: the account id on jira cloud, where the user names
+// are not accepted anymore, and the user name on jira server and data center
+func (f *JiraFinder) UserIdentifier(user User) string {
+	if f.isCloud() {
+		if user.AccountID != "" {
+			return user.AccountID
+		}
+		return user.Name
+	}
+
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.AccountID
+}
+
+// UsersIn gives the jql clause matching any of the users in the user field, like assignee in ("5b10ac8d82e05b22cc7d4ef5"),
+// empty without users
+func (f *JiraFinder) UsersIn(field string, users []User) string {
+	quoted := make([]string, 0, len(users))
+	for _, user := range users {
+		if id := f.UserIdentifier(user); id != "" {
+			quoted = append(quoted, quoteJql(id))
+		}
+	}
+
+	if len(quoted) == 0 {
+		return ""
+	}
+
+	return field + " in (" + strings.Join(quoted, ", ") + ")"
+}
@@ -0,0 +1,38 @@
+package jirafinder
+
+import (
+	"github.com/gojira/ferry/config"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestJiraFinder_UserIdentifier(t *testing.T) {
+	r := require.New(t)
+
+	jane := User{AccountID: "5b10a2844c20165700ede21g", Name: "jdoe", DisplayName: "Jane Doe"}
+	expected := []struct {
+		jiraURL    string
+		deployment string
+		identifier string
+	}{
+		{"https://your-domain.atlassian.net", "", "5b10a2844c20165700ede21g"},
+		{"https://jira.example.com", "", "jdoe"},
+		{"https://jira.example.com", "cloud", "5b10a2844c20165700ede21g"},
+		{"https://your-domain.atlassian.net", "Server", "jdoe"},
+	}
+
+	for _, e := range expected {
+		err, f := NewJiraFinder(&config.Configuration{JiraURL: e.jiraURL, Deployment: e.deployment})
+		r.NoError(err)
+		r.Equal(e.identifier, f.UserIdentifier(jane), "wrong identifier for %s %s", e.jiraURL, e.deployment)
+		r.Equal(`assignee in ("`+e.identifier+`")`, f.UsersIn("assignee", []User{jane}), "wrong clause for %s %s", e.jiraURL, e.deployment)
+	}
+
+	err, f := NewJiraFinder(&config.Configuration{JiraURL: "https://jira.example.com"})
+	r.NoError(err)
+	r.Equal("5b10ac8d82e05b22cc7d4ef5", f.UserIdentifier(User{AccountID: "5b10ac8d82e05b22cc7d4ef5"}), "expected the account id without name")
+	r.Equal("", f.UsersIn("assignee", []User{{DisplayName: "Jane Doe"}}), "expected no clause without identifier")
+
+	err, _ = NewJiraFinder(&config.Configuration{JiraURL: "https://jira.example.com", Deployment: "datacentre"})
+	r.Error(err, "expected invalid deployment error")
+}
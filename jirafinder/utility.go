@@ -2,10 +2,15 @@ package jirafinder
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
+	"io"
+	"log"
 
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -30,11 +35,514 @@ func writeToCsv(results [][]string, path string) error {
 	return errors.Wrapf(writer.WriteAll(results), "failed to write into to csv file")
 }
 
+// DedupeIssues removes the issues with an already seen key, keeping the order of the first occurrences
+func DedupeIssues(issues []JiraIssue) []JiraIssue {
+	seen := make(map[string]bool)
+	result := make([]JiraIssue, 0, len(issues))
+	for _, issue := range issues {
+		key := issue.Key()
+		if key != "" {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+
+		result = append(result, issue)
+	}
+
+	return result
+}
+
+// SortIssues sorts the issues by the value of the field, chronologically for the date times like "created"
+// and numerically when both values are numbers, the dates and numbers coming before the other values.
+// Issues with equal values keep their order
+func SortIssues(issues []JiraIssue, field string, ascending bool) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if ascending {
+			return lessIssue(issues[i], issues[j], field)
+		}
+		return lessIssue(issues[j], issues[i], field)
+	})
+}
+
+// lessIssue compares the issues by the time of the field when it is a date time, by its value otherwise
+func lessIssue(a JiraIssue, b JiraIssue, field string) bool {
+	errA, x := a.TimeField(field)
+	errB, y := b.TimeField(field)
+
+	switch {
+	case errA == nil && errB == nil:
+		return x.Before(y)
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	}
+
+	return lessValue(getFieldValue(field, a), getFieldValue(field, b))
+}
+
+// lessValue compares the values as numbers when both parse as numbers, as strings otherwise
+func lessValue(a string, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	}
+
+	return a < b
+}
+
+// jiraDurationUnits are the default jira time tracking units, a day being 8 hours and a week 5 days
+var jiraDurationUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 8 * time.Hour,
+	"w": 5 * 8 * time.Hour,
+}
+
+// parseJiraDuration parses the durations of the time tracking like "1w 2d 3h 30m"
+func parseJiraDuration(value string) (time.Duration, error) {
+	var total time.Duration
+	for _, part := range strings.Fields(value) {
+		unit, ok := jiraDurationUnits[part[len(part)-1:]]
+		if !ok {
+			return 0, errors.Errorf("invalid duration '%s'", value)
+		}
+
+		amount, err := strconv.ParseFloat(part[:len(part)-1], 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid duration '%s'", value)
+		}
+
+		total += time.Duration(amount * float64(unit))
+	}
+
+	return total, nil
+}
+
+// HoursPerAssignee sums the time tracking of the issues and their sub tasks per assignee,
+// the issues without assignee are summed as "Unassigned". The timeTrackingField is the TimeTrackingField
+// the sub tasks were enriched with, the original estimate when empty
+func HoursPerAssignee(issues <-chan JiraIssue, timeTrackingField string) map[string]time.Duration {
+	hours := make(map[string]time.Duration)
+	add := func(assignee string, value string) {
+		if assignee == "" || assignee == "N/A" {
+			assignee = "Unassigned"
+		}
+
+		if d, err := parseJiraDuration(value); err == nil {
+			hours[assignee] += d
+		}
+	}
+
+	for issue := range issues {
+		add(getValueFromField(issue.Data, "assignee"), getTimeTracking(issue.Data, timeTrackingField))
+		for _, subTask := range issue.SubTasks {
+			add(subTask.AssigneeName, subTask.TotalHours)
+		}
+	}
+
+	return hours
+}
+
+// truncate cuts the value to at most max runes, ending by an ellipsis when cut. A max of 0 or less keeps the value
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if max <= 0 || len(runes) <= max {
+		return value
+	}
+
+	return string(runes[:max-1]) + "…"
+}
+
+// ValueTransformer gives the value to export for a field, e.g. to normalize status names
+type ValueTransformer func(field string, value string) string
+
+// transformValues applies the transformer to the values of the rows, the first row being the header
+func transformValues(output [][]string, transform ValueTransformer) {
+	if len(output) == 0 {
+		return
+	}
+
+	header := output[0]
+	for _, row := range output[1:] {
+		for i := range row {
+			if i < len(header) {
+				row[i] = transform(header[i], row[i])
+			}
+		}
+	}
+}
+
+// pruneEmptyColumns drops the columns whose values are empty, "N/A" or the configured empty value for every issue,
+// the first row being the header
+func pruneEmptyColumns(output [][]string, emptyValue *string) [][]string {
+	if len(output) < 2 {
+		return output
+	}
+
+	missing := []string{"", "N/A"}
+	if emptyValue != nil {
+		missing = append(missing, *emptyValue)
+	}
+
+	keep := make([]int, 0, len(output[0]))
+	for i := range output[0] {
+		for _, row := range output[1:] {
+			if i < len(row) && !contains(missing, row[i]) {
+				keep = append(keep, i)
+				break
+			}
+		}
+	}
+
+	pruned := make([][]string, 0, len(output))
+	for _, row := range output {
+		values := make([]string, 0, len(keep))
+		for _, i := range keep {
+			if i < len(row) {
+				values = append(values, row[i])
+			}
+		}
+		pruned = append(pruned, values)
+	}
+
+	return pruned
+}
+
+type flusher interface {
+	Flush() error
+}
+
+// WriteNDJSON writes each issue received from the channel as one compact json object per line
+func WriteNDJSON(w io.Writer, issues <-chan JiraIssue) error {
+	encoder := json.NewEncoder(w)
+	for issue := range issues {
+		if err := encoder.Encode(issue); err != nil {
+			return errors.Wrapf(err, "failed to write issue as ndjson")
+		}
+
+		if f, ok := w.(flusher); ok {
+			if err := f.Flush(); err != nil {
+				return errors.Wrapf(err, "failed to flush ndjson output")
+			}
+		}
+	}
+
+	return nil
+}
+
+// streamFlushRows is the number of rows buffered by StreamCSV before being flushed
+const streamFlushRows = 100
+
+// StreamCSV writes the header of the fields then one row of those fields per issue as it is received from the channel,
+// like Search but without pruning the empty columns, the rows being flushed periodically so the issues
+// don't have to be held in memory
+func (f *JiraFinder) StreamCSV(w io.Writer, issues <-chan JiraIssue, fields []string) error {
+	head := header(fields, f.Config.RawColumns)
+	writer := csv.NewWriter(w)
+	if err := writer.Write(head); err != nil {
+		return errors.Wrapf(err, "failed to write csv header")
+	}
+
+	anonymizer := NewAnonymizer(f.Config.EmptyValue)
+	rows := 0
+	for issue := range issues {
+		issue.Fields = fields
+		row := download(issue, f.Config)
+		f.postProcess([][]string{head, row}, anonymizer)
+		if err := writer.Write(row); err != nil {
+			return errors.Wrapf(err, "failed to write issue %s as csv", issue.Key())
+		}
+
+		if rows++; rows%streamFlushRows == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return errors.Wrapf(err, "failed to flush csv output")
+			}
+		}
+	}
+
+	writer.Flush()
+	return errors.Wrapf(writer.Error(), "failed to flush csv output")
+}
+
+// header gives the columns of the export, each field being followed by its "<field>_raw" column when raw
+// like with RawColumns
+func header(fields []string, raw bool) []string {
+	if !raw {
+		return fields
+	}
+
+	columns := make([]string, 0, 2*len(fields))
+	for _, field := range fields {
+		columns = append(columns, field, field+"_raw")
+	}
+
+	return columns
+}
+
+// rawValue gives the json of the field as returned by jira, "null" when absent
+func rawValue(issue JiraIssue, field string) string {
+	val, ok := issue.Data[field]
+	if !ok {
+		val = issue.field(field)
+	}
+
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return ""
+	}
+
+	return string(raw)
+}
+
+// CrossTab counts the issues by the values of two fields, e.g. by assignee and status, missing values counting as "N/A"
+func CrossTab(issues []JiraIssue, rowField string, colField string) map[string]map[string]int {
+	table := make(map[string]map[string]int)
+	for _, issue := range issues {
+		row := crossTabValue(issue, rowField)
+		if table[row] == nil {
+			table[row] = make(map[string]int)
+		}
+		table[row][crossTabValue(issue, colField)]++
+	}
+
+	return table
+}
+
+func crossTabValue(issue JiraIssue, field string) string {
+	if value := getValueFromField(issue.Data, field); value != "" {
+		return value
+	}
+
+	return "N/A"
+}
+
+// FieldFillRates gives the fraction of the issues having a value for each field, e.g. to choose the exported columns,
+// 0 for every field without issues
+func FieldFillRates(issues []JiraIssue, fields []string) map[string]float64 {
+	rates := make(map[string]float64, len(fields))
+	for _, field := range fields {
+		filled := 0
+		for _, issue := range issues {
+			value := crossTabValue(issue, field)
+			// the top level fields like "key" are read like the export does
+			if top, ok := issue.Data[field].(string); ok {
+				value = top
+			}
+
+			if value != "" && value != "N/A" {
+				filled++
+			}
+		}
+
+		rates[field] = 0
+		if len(issues) > 0 {
+			rates[field] = float64(filled) / float64(len(issues))
+		}
+	}
+
+	return rates
+}
+
+// WriteCrossTab writes the cross tab as csv, one row per row value and one column per column value, both sorted
+func WriteCrossTab(w io.Writer, table map[string]map[string]int) error {
+	rows := make([]string, 0, len(table))
+	columns := make([]string, 0)
+	seen := make(map[string]bool)
+	for row, counts := range table {
+		rows = append(rows, row)
+		for column := range counts {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(rows)
+	sort.Strings(columns)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{""}, columns...)); err != nil {
+		return errors.Wrapf(err, "failed to write cross tab")
+	}
+
+	for _, row := range rows {
+		record := []string{row}
+		for _, column := range columns {
+			record = append(record, strconv.Itoa(table[row][column]))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return errors.Wrapf(err, "failed to write cross tab")
+		}
+	}
+
+	writer.Flush()
+	return errors.Wrapf(writer.Error(), "failed to write cross tab")
+}
+
+// paginationParams are set during the search and can't be given as extra params
+var paginationParams = []string{"startAt", "nextPageToken"}
+
+// mergeParams adds the extra params, the ones already set are kept with a warning
+func mergeParams(params map[string]string, extra map[string]string) {
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, ok := params[k]; ok || contains(paginationParams, k) {
+			log.Printf("warning: search param %s is reserved, '%s' is ignored", k, extra[k])
+			continue
+		}
+
+		params[k] = extra[k]
+	}
+}
+
+var (
+	jqlLiteral  = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+	jqlFunction = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*\(([^()]*)\)`)
+	jqlOffset   = regexp.MustCompile(`^[+-]?[0-9]+[yMwdhm]?$`)
+
+	// jqlKeywords can be followed by a parenthesis without being a function
+	jqlKeywords = []string{"and", "or", "not", "in", "was", "changed", "by", "from", "to", "after", "before", "on", "during"}
+
+	// jqlDateFunctions accept an optional offset like "-7d"
+	jqlDateFunctions = []string{"startOfDay", "endOfDay", "startOfWeek", "endOfWeek", "startOfMonth", "endOfMonth", "startOfYear", "endOfYear"}
+
+	jqlFunctions = append([]string{
+		"now", "currentUser", "currentLogin", "lastLogin", "membersOf", "openSprints", "closedSprints", "futureSprints",
+		"issueHistory", "linkedIssues", "votedIssues", "watchedIssues", "updatedBy", "releasedVersions",
+		"latestReleasedVersion", "unreleasedVersions", "earliestUnreleasedVersion", "componentsLeadByUser",
+		"projectsLeadByUser", "projectsWhereUserHasPermission", "projectsWhereUserHasRole", "cascadeOption",
+		"standardIssueTypes", "subtaskIssueTypes", "issuesWithRemoteLinksByGlobalId", "parentEpic",
+	}, jqlDateFunctions...)
+)
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsFold tells if the value is one of the values, ignoring the case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// editDistance gives the number of single letter edits turning a into b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous = current
+	}
+
+	return previous[len(b)]
+}
+
+// nearestJqlFunction gives the built-in function the name is a typo of, empty when it is too far from all of them
+func nearestJqlFunction(name string) string {
+	allowed := 1
+	if len(name) > 5 {
+		allowed = 2
+	}
+
+	for _, function := range jqlFunctions {
+		if editDistance(strings.ToLower(name), strings.ToLower(function)) <= allowed {
+			return function
+		}
+	}
+
+	return ""
+}
+
+// validateJql catches misspelled built-in functions, malformed date offsets and unbalanced parenthesis.
+// It is not a full JQL parser, string literals are ignored and the other unknown functions, like the ones of the apps, are left to Jira.
+func validateJql(jql string) error {
+	stripped := jqlLiteral.ReplaceAllString(jql, "''")
+
+	if strings.Count(stripped, "(") != strings.Count(stripped, ")") {
+		return errors.Errorf("invalid jql '%s': unbalanced parenthesis", jql)
+	}
+
+	for _, match := range jqlFunction.FindAllStringSubmatch(stripped, -1) {
+		name := strings.ToLower(match[1])
+		if contains(jqlKeywords, name) {
+			continue
+		}
+
+		if !containsFold(jqlFunctions, name) {
+			if nearest := nearestJqlFunction(name); nearest != "" {
+				return errors.Errorf("invalid jql '%s': unknown function %s(), did you mean %s()", jql, match[1], nearest)
+			}
+
+			log.Printf("warning: jql function %s() is not a built-in one, leaving it to Jira", match[1])
+			continue
+		}
+
+		arg := strings.TrimSpace(match[2])
+		if containsFold(jqlDateFunctions, name) && arg != "" && arg != "''" && !jqlOffset.MatchString(arg) {
+			return errors.Errorf("invalid jql '%s': malformed offset '%s' in %s()", jql, arg, match[1])
+		}
+	}
+
+	return nil
+}
+
 func getJql(filters map[string]string) string {
 	index := 0
 	totalCount := len(filters)
+	keys := make([]string, 0, totalCount)
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	var b strings.Builder
-	for k, v := range filters {
+	for _, k := range keys {
+		v := filters[k]
 		index++
 		if strings.Contains(v, ",") {
 			valSlice := strings.Split(v, ",")
@@ -52,6 +560,34 @@ func getJql(filters map[string]string) string {
 	return b.String()
 }
 
+// LabelsIn gives the jql clause matching any of the labels, like labels in ("backend", "tech debt"), empty without labels
+func LabelsIn(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, 0, len(labels))
+	for _, label := range labels {
+		quoted = append(quoted, quoteJql(label))
+	}
+
+	return "labels in (" + strings.Join(quoted, ", ") + ")"
+}
+
+// filterValue gives the jql value of a filter, the unquoted yaml and toml values like `Sprint: 42` being numbers
+func filterValue(value interface{}) string {
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// quoteJql quotes the value as a jql string, escaping its quotes and backslashes
+func quoteJql(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
 func getInFilterValue(values []string) string {
 	index := 0
 	totalCount := len(values)
@@ -83,6 +619,17 @@ func getFieldValue(field string, issue JiraIssue) string {
 	return getValueFromField(issue.Data, field)
 }
 
+// fallbackValue gives the value of the first candidate field which is set, "N/A" when none is
+func fallbackValue(issue JiraIssue, candidates []string) string {
+	for _, candidate := range candidates {
+		if value := getFieldValue(candidate, issue); value != "" && value != "N/A" {
+			return value
+		}
+	}
+
+	return "N/A"
+}
+
 // GetValueFromField gets the value from the 'fields' property of the issue
 func getValueFromField(issue map[string]interface{}, field string) string {
 	val, ok := issue["fields"]
@@ -91,27 +638,57 @@ func getValueFromField(issue map[string]interface{}, field string) string {
 
 		val, ok := fieldsMap[field]
 		if ok {
-			if strings.ToLower(field) == "created" {
-				dateVal, _ := time.Parse("2006-01-02T15:04:05.999-0700", val.(string))
-				return dateVal.Format("02/Jan/06")
+			val = unwrapSingle(val)
+			if date, isString := val.(string); isString && strings.ToLower(field) == "created" {
+				return formatDate(date, time.Local)
 			}
-			return strings.Replace(getValue(val, field), ",", "", -1)
+			return getValue(val, field)
 		}
 	}
 	return "N/A"
 }
 
+// unwrapSingle gives the element of a one element array, which some instances return for single value fields
+func unwrapSingle(val interface{}) interface{} {
+	if list, ok := val.([]interface{}); ok && len(list) == 1 {
+		return list[0]
+	}
+
+	return val
+}
+
+// wrapList gives the value as an array, which some instances return as a single value for array fields
+func wrapList(val interface{}) []interface{} {
+	switch v := val.(type) {
+	case []interface{}:
+		return v
+	case nil:
+		return nil
+	}
+
+	return []interface{}{val}
+}
+
 // GetValue gets the value based on the type of interface
 func getValue(val interface{}, fieldName string) string {
 	var result string
 	arrayVal, isArray := val.([]interface{})
 	mapVal, isMap := val.(map[string]interface{})
 	if isArray {
-		result = arrayVal[0].(map[string]interface{})["value"].(string)
+		values := make([]string, 0, len(arrayVal))
+		for _, element := range arrayVal {
+			values = append(values, getValue(element, fieldName))
+		}
+		result = strings.Join(values, ", ")
+	} else if isMap && nestedString(mapVal, "type") == "doc" {
+		result = textFromField(mapVal)
 	} else if isMap {
-		tmpResult, ok := mapVal[getNestedMapKeyName(fieldName)]
+		tmpResult, ok := mapVal[getNestedMapKeyName(fieldName)].(string)
+		if !ok {
+			tmpResult, ok = fallbackNestedValue(mapVal)
+		}
 		if ok {
-			result = tmpResult.(string)
+			result = tmpResult
 		}
 	} else if val != nil {
 		result = fmt.Sprint(val)
@@ -120,9 +697,58 @@ func getValue(val interface{}, fieldName string) string {
 	return result
 }
 
+// formatDate gives the date of the jira time in the location
+func formatDate(value string, loc *time.Location) string {
+	dateVal, _ := time.Parse(jiraTimeLayout, value)
+	return dateVal.In(loc).Format("02/Jan/06")
+}
+
+// getDateFromField gives the date of the time field in the named time zone, "N/A" when not set
+func getDateFromField(issue map[string]interface{}, field string, timeZone string) string {
+	fields, ok := issue["fields"].(map[string]interface{})
+	if !ok {
+		return "N/A"
+	}
+
+	val, ok := fields[field].(string)
+	if !ok {
+		return "N/A"
+	}
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		loc = time.Local
+	}
+
+	return formatDate(val, loc)
+}
+
+// getTimeTracking gets the given sub field of the time tracking, the original estimate by default
+func getTimeTracking(issue map[string]interface{}, subField string) string {
+	if subField == "" {
+		return getValueFromField(issue, "timetracking")
+	}
+
+	fields, ok := issue["fields"].(map[string]interface{})
+	if !ok {
+		return "N/A"
+	}
+
+	timeTracking, ok := fields["timetracking"].(map[string]interface{})
+	if !ok {
+		return "N/A"
+	}
+
+	if val, ok := timeTracking[subField].(string); ok {
+		return val
+	}
+
+	return ""
+}
+
 // GetNestedMapKeyName gets the nested field name to search for a parent name
 func getNestedMapKeyName(fieldName string) string {
-	if strings.ToLower(fieldName) == "assignee" || strings.ToLower(fieldName) == "reporter" {
+	if strings.ToLower(fieldName) == "assignee" || strings.ToLower(fieldName) == "reporter" || strings.ToLower(fieldName) == "creator" {
 		return "displayName"
 	}
 
@@ -130,6 +756,14 @@ func getNestedMapKeyName(fieldName string) string {
 		return "name"
 	}
 
+	if strings.ToLower(fieldName) == "versions" || strings.ToLower(fieldName) == "fixversions" || strings.ToLower(fieldName) == "components" {
+		return "name"
+	}
+
+	if strings.ToLower(fieldName) == "project" || strings.ToLower(fieldName) == "resolution" {
+		return "name"
+	}
+
 	if strings.ToLower(fieldName) == "timetracking" {
 		return "originalEstimate"
 	}
@@ -137,6 +771,18 @@ func getNestedMapKeyName(fieldName string) string {
 	return "value"
 }
 
+// fallbackNestedValue gets the name of users, versions and other objects of fields whose shape is not known,
+// like the project, version and group pickers
+func fallbackNestedValue(val map[string]interface{}) (string, bool) {
+	for _, key := range []string{"displayName", "name", "value"} {
+		if name, ok := val[key].(string); ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
 // GetDevTaskAssigneeName gets Assignee name of the dev task, exclude code review task
 func getDevTaskAssigneeName(subTasks []SubTask) string {
 	for _, subTask := range subTasks {
@@ -187,6 +833,32 @@ func isBug(issueType string) bool {
 	return strings.ToLower(issueType) == "bug" || strings.ToLower(issueType) == "functional bug" || strings.ToLower(issueType) == "production issue"
 }
 
+// getUserFromField gives the display name of the user set in the field, empty when not set.
+// The users of a multi user field, like a team, are joined
+func getUserFromField(issue map[string]interface{}, field string) string {
+	fields, ok := issue["fields"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	switch val := unwrapSingle(fields[field]).(type) {
+	case map[string]interface{}:
+		return parseUser(val).String()
+	case []interface{}:
+		names := make([]string, 0, len(val))
+		for _, u := range val {
+			if name := parseUser(u).String(); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	case string:
+		return val
+	}
+
+	return ""
+}
+
 func getDeveloperNameFromLog(issue map[string]interface{}) string {
 	if issue == nil {
 		return ""
@@ -1,7 +1,15 @@
 package jirafinder
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gojira/ferry/config"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type MockHTTPRequest struct {
@@ -151,6 +159,574 @@ func TestGetFieldValueFromField(t *testing.T) {
 	}
 }
 
+func TestGetFieldValueKeepsCommas(t *testing.T) {
+	issueMap := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"reporter": map[string]interface{}{"displayName": "Smith, John"},
+			"budget":   "$1,000",
+		},
+	}
+
+	fieldValue := getValueFromField(issueMap, "reporter")
+	if fieldValue != "Smith, John" {
+		t.Errorf("Wrong field value. got : %s, want : %s", fieldValue, "Smith, John")
+	}
+
+	fieldValue = getValueFromField(issueMap, "budget")
+	if fieldValue != "$1,000" {
+		t.Errorf("Wrong field value. got : %s, want : %s", fieldValue, "$1,000")
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	issues := make(chan JiraIssue, 3)
+	for _, key := range []string{"POS-1", "POS-2", "POS-3"} {
+		issues <- JiraIssue{Data: map[string]interface{}{"key": key}}
+	}
+	close(issues)
+
+	var b bytes.Buffer
+	if err := WriteNDJSON(&b, issues); err != nil {
+		t.Fatalf("WriteNDJSON failed: %s", err)
+	}
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(&b)
+	for scanner.Scan() {
+		var issue JiraIssue
+		if err := json.Unmarshal(scanner.Bytes(), &issue); err != nil {
+			t.Fatalf("Invalid ndjson line %s: %s", scanner.Text(), err)
+		}
+		lines = append(lines, issue.Data["key"].(string))
+	}
+
+	if len(lines) != 3 || lines[0] != "POS-1" || lines[2] != "POS-3" {
+		t.Errorf("Wrong ndjson lines, got : %v, want : %v", lines, []string{"POS-1", "POS-2", "POS-3"})
+	}
+}
+
+func TestStreamCSV(t *testing.T) {
+	fields := []string{"key", "summary", "reporter"}
+	issues := make(chan JiraIssue)
+	go func() {
+		for i, summary := range []string{"Fix login", "Add export, csv", "Update docs"} {
+			issues <- JiraIssue{Data: map[string]interface{}{
+				"key":    fmt.Sprintf("POS-%d", i+1),
+				"fields": map[string]interface{}{"summary": summary, "reporter": map[string]interface{}{"displayName": "Jane Doe"}},
+			}}
+		}
+		close(issues)
+	}()
+
+	f := &JiraFinder{Config: config.Configuration{FieldsToRetrieve: []string{"summary"}, AnonymizeFields: []string{"reporter"}}}
+	f.Transformer = func(field string, value string) string {
+		if field == "summary" {
+			return strings.ToUpper(value)
+		}
+		return value
+	}
+
+	var b bytes.Buffer
+	if err := f.StreamCSV(&b, issues, fields); err != nil {
+		t.Fatalf("StreamCSV failed: %s", err)
+	}
+
+	want := "key,summary,reporter\nPOS-1,FIX LOGIN,User 1\nPOS-2,\"ADD EXPORT, CSV\",User 1\nPOS-3,UPDATE DOCS,User 1\n"
+	if b.String() != want {
+		t.Errorf("Wrong csv, got : %q, want : %q", b.String(), want)
+	}
+}
+
+func TestGetJqlStableOrder(t *testing.T) {
+	filters := map[string]string{"Sprint": "Sprint 1", "Project": "POS", "IssueType": "Bug, Story"}
+	expected := "IssueType in ('Bug','Story') AND Project='POS' AND Sprint='Sprint 1'"
+
+	for i := 0; i < 10; i++ {
+		if jql := getJql(filters); jql != expected {
+			t.Fatalf("Wrong jql, got : %s, want : %s", jql, expected)
+		}
+	}
+}
+
+func TestGetJqlQuotesFunctionLikeValues(t *testing.T) {
+	expected := "summary='foo(bar)'"
+
+	if jql := getJql(map[string]string{"summary": "foo(bar)"}); jql != expected {
+		t.Errorf("Wrong jql, got : %s, want : %s", jql, expected)
+	}
+}
+
+func TestValidateJqlMacro(t *testing.T) {
+	valid := []string{
+		"project = POS AND created >= startOfDay(-7d)",
+		"assignee = currentUser() AND sprint in openSprints()",
+		"IssueType in ('Bug','Story') AND summary ~ 'fix (urgent'",
+		"issueFunction in subtasksOf('project = POS') AND created >= startOfDay(-7d)",
+		getJql(map[string]string{"summary": "foo(bar)", "Project": "POS"}),
+	}
+
+	for _, jql := range valid {
+		if err := validateJql(jql); err != nil {
+			t.Errorf("Expected valid jql %s, got : %s", jql, err)
+		}
+	}
+}
+
+func TestValidateJqlMalformedMacro(t *testing.T) {
+	invalid := map[string]string{
+		"created >= startOfDay(-7x)":          "malformed offset",
+		"created >= startOfDya(-7d)":          "unknown function startOfDya(), did you mean startOfDay()",
+		"assignee = currentUsr()":             "unknown function currentUsr()",
+		"created >= startOfDay(-7d":           "unbalanced parenthesis",
+		"assignee = currentUser() AND (a=b))": "unbalanced parenthesis",
+	}
+
+	for jql, expected := range invalid {
+		err := validateJql(jql)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Wrong validation of %s, got : %v, want : %s", jql, err, expected)
+		}
+	}
+}
+
+func TestGetTimeTracking(t *testing.T) {
+	issueMap := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"timetracking": map[string]interface{}{
+				"originalEstimate":  "16h",
+				"remainingEstimate": "4h",
+				"timeSpent":         "12h",
+			},
+		},
+	}
+
+	expected := map[string]string{"": "16h", "originalEstimate": "16h", "remainingEstimate": "4h", "timeSpent": "12h"}
+	for subField, want := range expected {
+		if got := getTimeTracking(issueMap, subField); got != want {
+			t.Errorf("Wrong %s time tracking, got : %s, want : %s", subField, got, want)
+		}
+	}
+}
+
+func TestDedupeIssues(t *testing.T) {
+	issue := func(key string, summary string) JiraIssue {
+		return JiraIssue{Data: map[string]interface{}{"key": key, "fields": map[string]interface{}{"summary": summary}}}
+	}
+
+	fromFilter := []JiraIssue{issue("POS-1", "first"), issue("POS-2", "first"), issue("POS-3", "first")}
+	fromSubTasks := []JiraIssue{issue("POS-2", "second"), issue("POS-4", "second"), issue("POS-1", "second")}
+
+	result := DedupeIssues(append(fromFilter, fromSubTasks...))
+
+	keys := make([]string, 0)
+	for _, i := range result {
+		keys = append(keys, i.Key())
+		if i.Key() != "POS-4" && getValueFromField(i.Data, "summary") != "first" {
+			t.Errorf("Expected first occurrence of %s to be kept", i.Key())
+		}
+	}
+
+	if strings.Join(keys, ",") != "POS-1,POS-2,POS-3,POS-4" {
+		t.Errorf("Wrong deduplicated issues, got : %v, want : %s", keys, "POS-1,POS-2,POS-3,POS-4")
+	}
+}
+
+func TestSortIssues(t *testing.T) {
+	issue := func(key string, points interface{}, summary string) JiraIssue {
+		return JiraIssue{Data: map[string]interface{}{
+			"key":    key,
+			"fields": map[string]interface{}{"customfield_10026": points, "summary": summary},
+		}}
+	}
+	keys := func(issues []JiraIssue) []string {
+		result := make([]string, 0, len(issues))
+		for _, i := range issues {
+			result = append(result, i.Key())
+		}
+		return result
+	}
+
+	issues := []JiraIssue{
+		issue("POS-1", 13.0, "beta"),
+		issue("POS-2", 2.0, "alpha"),
+		issue("POS-3", nil, "Gamma"),
+		issue("POS-4", 5.0, "delta"),
+	}
+
+	SortIssues(issues, "customfield_10026", true)
+	if got, want := keys(issues), []string{"POS-2", "POS-4", "POS-1", "POS-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong numeric ascending sort, got : %v, want : %v", got, want)
+	}
+
+	SortIssues(issues, "customfield_10026", false)
+	if got, want := keys(issues), []string{"POS-3", "POS-1", "POS-4", "POS-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong numeric descending sort, got : %v, want : %v", got, want)
+	}
+
+	SortIssues(issues, "summary", true)
+	if got, want := keys(issues), []string{"POS-3", "POS-2", "POS-1", "POS-4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong lexical sort, got : %v, want : %v", got, want)
+	}
+}
+
+func TestSortIssuesByDate(t *testing.T) {
+	issue := func(key string, created interface{}) JiraIssue {
+		return JiraIssue{Data: map[string]interface{}{
+			"key":    key,
+			"fields": map[string]interface{}{"created": created},
+		}}
+	}
+
+	// the formatted dates like "05/Sep/20" and "17/Aug/20" are not in chronological order
+	issues := []JiraIssue{
+		issue("POS-1", "2020-09-05T10:00:00.000+0000"),
+		issue("POS-2", nil),
+		issue("POS-3", "2020-08-17T05:13:32.383+0000"),
+		issue("POS-4", "2021-01-02T08:00:00.000+0000"),
+	}
+
+	SortIssues(issues, "created", true)
+	got := make([]string, 0, len(issues))
+	for _, i := range issues {
+		got = append(got, i.Key())
+	}
+	if want := []string{"POS-3", "POS-1", "POS-4", "POS-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong date sort, got : %v, want : %v", got, want)
+	}
+}
+
+func TestParseJiraDuration(t *testing.T) {
+	expected := map[string]time.Duration{
+		"3h":           3 * time.Hour,
+		"1d 4h":        12 * time.Hour,
+		"1w 2d 3h 30m": 59*time.Hour + 30*time.Minute,
+		"":             0,
+	}
+
+	for value, want := range expected {
+		if got, err := parseJiraDuration(value); err != nil || got != want {
+			t.Errorf("Wrong duration of '%s', got : %s (%v), want : %s", value, got, err, want)
+		}
+	}
+
+	if _, err := parseJiraDuration("N/A"); err == nil {
+		t.Errorf("Expected invalid duration error")
+	}
+}
+
+func TestHoursPerAssignee(t *testing.T) {
+	issues := make(chan JiraIssue, 2)
+	issues <- JiraIssue{
+		Data: map[string]interface{}{"fields": map[string]interface{}{
+			"assignee":     map[string]interface{}{"displayName": "Dev1"},
+			"timetracking": map[string]interface{}{"originalEstimate": "2h"},
+		}},
+		SubTasks: []SubTask{
+			{AssigneeName: "Dev1", TotalHours: "1d"},
+			{AssigneeName: "Dev2", TotalHours: "4h"},
+		},
+	}
+	issues <- JiraIssue{
+		Data: map[string]interface{}{"fields": map[string]interface{}{
+			"assignee":     nil,
+			"timetracking": map[string]interface{}{"originalEstimate": "3h"},
+		}},
+		SubTasks: []SubTask{
+			{AssigneeName: "Dev2", TotalHours: "1h 30m"},
+			{AssigneeName: "N/A", TotalHours: "2h"},
+		},
+	}
+	close(issues)
+
+	hours := HoursPerAssignee(issues, "")
+	expected := map[string]time.Duration{
+		"Dev1":       10 * time.Hour,
+		"Dev2":       5*time.Hour + 30*time.Minute,
+		"Unassigned": 5 * time.Hour,
+	}
+
+	if len(hours) != len(expected) {
+		t.Errorf("Wrong hours per assignee, got : %v, want : %v", hours, expected)
+	}
+	for assignee, want := range expected {
+		if hours[assignee] != want {
+			t.Errorf("Wrong hours of %s, got : %s, want : %s", assignee, hours[assignee], want)
+		}
+	}
+}
+
+func TestHoursPerAssigneeTimeTrackingField(t *testing.T) {
+	issues := make(chan JiraIssue, 1)
+	issues <- JiraIssue{
+		Data: map[string]interface{}{"fields": map[string]interface{}{
+			"assignee":     map[string]interface{}{"displayName": "Dev1"},
+			"timetracking": map[string]interface{}{"originalEstimate": "2h", "timeSpent": "5h"},
+		}},
+		SubTasks: []SubTask{
+			{AssigneeName: "Dev1", TotalHours: "3h"},
+		},
+	}
+	close(issues)
+
+	hours := HoursPerAssignee(issues, "timeSpent")
+	if hours["Dev1"] != 8*time.Hour {
+		t.Errorf("Wrong hours of the time spent, got : %s, want : %s", hours["Dev1"], 8*time.Hour)
+	}
+}
+
+func TestTruncateMultibyte(t *testing.T) {
+	expected := map[int]string{
+		0:  "Café crème brûlée",
+		8:  "Café cr…",
+		5:  "Café…",
+		17: "Café crème brûlée",
+	}
+
+	for max, want := range expected {
+		if got := truncate("Café crème brûlée", max); got != want {
+			t.Errorf("Wrong truncation at %d, got : %s, want : %s", max, got, want)
+		}
+	}
+
+	if got := truncate("日本語のテキスト", 4); got != "日本語…" {
+		t.Errorf("Wrong truncation, got : %s, want : %s", got, "日本語…")
+	}
+}
+
+func TestTransformValues(t *testing.T) {
+	output := [][]string{
+		{"key", "status"},
+		{"POS-1", "In Progress"},
+		{"POS-2", "done"},
+	}
+
+	transformValues(output, func(field string, value string) string {
+		if field == "status" {
+			return strings.ToUpper(value)
+		}
+		return value
+	})
+
+	if output[1][1] != "IN PROGRESS" || output[2][1] != "DONE" {
+		t.Errorf("Wrong transformed status, got : %v, want : %v", output[1:], []string{"IN PROGRESS", "DONE"})
+	}
+	if output[0][1] != "status" || output[1][0] != "POS-1" {
+		t.Errorf("Header and other fields should not be transformed, got : %v", output)
+	}
+}
+
+func TestGetDateFromField(t *testing.T) {
+	issue := map[string]interface{}{
+		"fields": map[string]interface{}{"created": "2020-08-17T23:30:00.000+0000"},
+	}
+
+	if got := getDateFromField(issue, "created", "UTC"); got != "17/Aug/20" {
+		t.Errorf("Wrong UTC date, got : %s, want : %s", got, "17/Aug/20")
+	}
+
+	if got := getDateFromField(issue, "created", "Asia/Kolkata"); got != "18/Aug/20" {
+		t.Errorf("Wrong Asia/Kolkata date, got : %s, want : %s", got, "18/Aug/20")
+	}
+
+	if got := getDateFromField(issue, "resolutiondate", "UTC"); got != "N/A" {
+		t.Errorf("Wrong missing date, got : %s, want : %s", got, "N/A")
+	}
+}
+
+func TestCrossTab(t *testing.T) {
+	issue := func(assignee string, status string) JiraIssue {
+		fields := map[string]interface{}{"status": map[string]interface{}{"name": status}}
+		if assignee != "" {
+			fields["assignee"] = map[string]interface{}{"displayName": assignee}
+		}
+		return JiraIssue{Data: map[string]interface{}{"fields": fields}}
+	}
+
+	issues := []JiraIssue{
+		issue("Jane Doe", "Open"),
+		issue("Jane Doe", "Done"),
+		issue("Jane Doe", "Done"),
+		issue("John Roe", "Open"),
+		issue("", "Open"),
+	}
+
+	table := CrossTab(issues, "assignee", "status")
+	want := map[string]map[string]int{
+		"Jane Doe": {"Open": 1, "Done": 2},
+		"John Roe": {"Open": 1},
+		"N/A":      {"Open": 1},
+	}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("Wrong cross tab, got : %v, want : %v", table, want)
+	}
+
+	var out bytes.Buffer
+	if err := WriteCrossTab(&out, table); err != nil {
+		t.Fatalf("WriteCrossTab resulting to error: %s", err)
+	}
+
+	csv := ",Done,Open\nJane Doe,2,1\nJohn Roe,0,1\nN/A,0,1\n"
+	if out.String() != csv {
+		t.Errorf("Wrong cross tab csv, got : %q, want : %q", out.String(), csv)
+	}
+}
+
+func TestFieldFillRates(t *testing.T) {
+	var issues []JiraIssue
+	for _, body := range []string{
+		`{"key": "POS-1", "fields": {"summary": "Fix issue", "assignee": {"displayName": "Jane Doe"}, "customfield_10016": 3}}`,
+		`{"key": "POS-2", "fields": {"summary": "Add report", "assignee": null, "customfield_10016": 5}}`,
+		`{"key": "POS-3", "fields": {"summary": "Update docs", "assignee": null}}`,
+		`{"key": "POS-4", "fields": {"summary": "Release"}}`,
+	} {
+		var data map[string]interface{}
+		json.Unmarshal([]byte(body), &data)
+		issues = append(issues, JiraIssue{Data: data})
+	}
+
+	rates := FieldFillRates(issues, []string{"key", "summary", "assignee", "customfield_10016", "duedate"})
+	want := map[string]float64{"key": 1, "summary": 1, "assignee": 0.25, "customfield_10016": 0.5, "duedate": 0}
+	if !reflect.DeepEqual(rates, want) {
+		t.Errorf("Wrong fill rates, got : %v, want : %v", rates, want)
+	}
+
+	rates = FieldFillRates(nil, []string{"summary"})
+	if rates["summary"] != 0 {
+		t.Errorf("Wrong fill rate without issues, got : %v, want : %v", rates["summary"], 0)
+	}
+}
+
+func TestLabelsIn(t *testing.T) {
+	var issue JiraIssue
+	json.Unmarshal([]byte(`{"fields": {"labels": ["backend", "tech debt", "say \"hi\""]}}`), &issue.Data)
+
+	want := `labels in ("backend", "tech debt", "say \"hi\"")`
+	if got := LabelsIn(issue.Labels()); got != want {
+		t.Errorf("Wrong labels clause, got : %s, want : %s", got, want)
+	}
+
+	if got := LabelsIn(nil); got != "" {
+		t.Errorf("Wrong clause without labels, got : %s, want : %s", got, "")
+	}
+}
+
+func TestPruneEmptyColumns(t *testing.T) {
+	output := [][]string{
+		{"key", "resolution", "status"},
+		{"POS-1", "N/A", "Open"},
+		{"POS-2", "N/A", ""},
+	}
+
+	want := [][]string{
+		{"key", "status"},
+		{"POS-1", "Open"},
+		{"POS-2", ""},
+	}
+
+	if got := pruneEmptyColumns(output, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong pruned output, got : %v, want : %v", got, want)
+	}
+	if output[1][1] != "N/A" {
+		t.Errorf("Original output should not be modified, got : %v", output)
+	}
+
+	emptyValue := "-"
+	output = [][]string{
+		{"key", "resolution"},
+		{"POS-1", "-"},
+	}
+	want = [][]string{{"key"}, {"POS-1"}}
+	if got := pruneEmptyColumns(output, &emptyValue); !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong pruned output with empty value, got : %v, want : %v", got, want)
+	}
+}
+
+func TestGetValueMultiUser(t *testing.T) {
+	var val interface{}
+	json.Unmarshal([]byte(`[{"accountId": "1", "displayName": "Jane Doe"}, {"accountId": "2", "displayName": "John Roe"}]`), &val)
+
+	if got := getValue(val, "customfield_10200"); got != "Jane Doe, John Roe" {
+		t.Errorf("Wrong multi user value, got : %s, want : %s", got, "Jane Doe, John Roe")
+	}
+}
+
+func TestGetValueMultiVersion(t *testing.T) {
+	var val interface{}
+	json.Unmarshal([]byte(`[{"id": "10000", "name": "1.0", "released": true}, {"id": "10001", "name": "1.1", "released": false}]`), &val)
+
+	if got := getValue(val, "fixVersions"); got != "1.0, 1.1" {
+		t.Errorf("Wrong multi version value, got : %s, want : %s", got, "1.0, 1.1")
+	}
+
+	json.Unmarshal([]byte(`[{"id": "10100", "value": "Critical"}]`), &val)
+	if got := getValue(val, "customfield_10030"); got != "Critical" {
+		t.Errorf("Wrong multi select value, got : %s, want : %s", got, "Critical")
+	}
+}
+
+func TestGetValueFromFieldContainers(t *testing.T) {
+	var issue map[string]interface{}
+	json.Unmarshal([]byte(`{"fields": {
+  "assignee": [{"accountId": "1", "displayName": "Jane Doe"}],
+  "created": ["2020-08-17T08:13:32.383+0000"],
+  "fixVersions": {"id": "10000", "name": "1.0"}
+}}`), &issue)
+
+	if got := getValueFromField(issue, "assignee"); got != "Jane Doe" {
+		t.Errorf("Wrong single user returned as an array, got : %s, want : %s", got, "Jane Doe")
+	}
+	if got := getUserFromField(issue, "assignee"); got != "Jane Doe" {
+		t.Errorf("Wrong single user field returned as an array, got : %s, want : %s", got, "Jane Doe")
+	}
+	if got := getValueFromField(issue, "created"); got != "17/Aug/20" {
+		t.Errorf("Wrong single date returned as an array, got : %s, want : %s", got, "17/Aug/20")
+	}
+	if got := getValueFromField(issue, "fixVersions"); got != "1.0" {
+		t.Errorf("Wrong array field returned as a single value, got : %s, want : %s", got, "1.0")
+	}
+}
+
+func TestGetUserFromFieldMultiUser(t *testing.T) {
+	var issue map[string]interface{}
+	json.Unmarshal([]byte(`{"fields": {
+  "customfield_10040": [
+    {"accountId": "5b10a2844c20165700ede21g", "displayName": "Jane Doe"},
+    {"accountId": "5b10ac8d82e05b22cc7d4ef5"}
+  ]
+}}`), &issue)
+
+	want := "Jane Doe, 5b10ac8d82e05b22cc7d4ef5"
+	if got := getUserFromField(issue, "customfield_10040"); got != want {
+		t.Errorf("Wrong team field users, got : %s, want : %s", got, want)
+	}
+}
+
+func TestGetValuePickers(t *testing.T) {
+	pickers := []struct {
+		name  string
+		field string
+		body  string
+		want  string
+	}{
+		{"project picker", "customfield_10060", `{"id": "10000", "key": "POS", "name": "Point of Sale", "projectTypeKey": "software"}`, "Point of Sale"},
+		{"version picker", "customfield_10061", `[{"id": "10010", "name": "1.0", "released": true}, {"id": "10011", "name": "1.1", "released": false}]`, "1.0, 1.1"},
+		{"group picker", "customfield_10062", `{"name": "jira-developers", "groupId": "276f955c-63d7-42c8-9520-92d01dca0625"}`, "jira-developers"},
+		{"cascading select", "customfield_10063", `{"id": "10100", "value": "Hardware", "child": {"id": "10101", "value": "Printer"}}`, "Hardware"},
+		{"unknown shape", "customfield_10064", `{"id": "7", "displayName": "Team A", "avatarUrl": "https://example.com/a.png"}`, "Team A"},
+		{"value fallback", "priority", `{"id": "2", "value": "High"}`, "High"},
+		{"null value", "customfield_10065", `{"value": null}`, ""},
+		{"project", "project", `{"id": "10000", "key": "POS", "name": "Point of Sale"}`, "Point of Sale"},
+	}
+
+	for _, p := range pickers {
+		var val interface{}
+		json.Unmarshal([]byte(p.body), &val)
+		if got := getValue(val, p.field); got != p.want {
+			t.Errorf("Wrong %s value, got : %s, want : %s", p.name, got, p.want)
+		}
+	}
+}
+
 func TestGetNestedMapKeyName(t *testing.T) {
 	result := getNestedMapKeyName("Assignee")
 
@@ -164,6 +740,12 @@ func TestGetNestedMapKeyName(t *testing.T) {
 		ThrowError(t, "worng reporter nested name", "displayName", result)
 	}
 
+	result = getNestedMapKeyName("creator")
+
+	if result != "displayName" {
+		ThrowError(t, "worng creator nested name", "displayName", result)
+	}
+
 	result = getNestedMapKeyName("IssueType")
 
 	if result != "name" {
@@ -0,0 +1,119 @@
+package jirafinder
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+// worklogListSize is the maximum number of worklog ids jira accepts in one /worklog/list request
+const worklogListSize = 1000
+
+// Worklog is the time logged by a user on an issue
+type Worklog struct {
+	ID               string
+	IssueID          string
+	Author           string
+	Started          time.Time
+	TimeSpentSeconds int64
+	Comment          string
+}
+
+// GetUpdatedWorklogs gives the worklogs created or updated since the given time, e.g. to sync the time tracking
+// incrementally. The ids of the updated worklogs are paged, their details being retrieved in batches
+func (f *JiraFinder) GetUpdatedWorklogs(since time.Time) (error, []Worklog) {
+	err, ids := f.updatedWorklogIDs(since)
+	if err != nil {
+		return err, nil
+	}
+
+	worklogs := make([]Worklog, 0, len(ids))
+	for start := 0; start < len(ids); start += worklogListSize {
+		end := start + worklogListSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		err, batch := f.listWorklogs(ids[start:end])
+		if err != nil {
+			return err, nil
+		}
+		worklogs = append(worklogs, batch...)
+	}
+
+	return nil, worklogs
+}
+
+// updatedWorklogIDs pages the ids of the worklogs updated since the given time, each page starting at the end of the previous one
+func (f *JiraFinder) updatedWorklogIDs(since time.Time) (error, []int64) {
+	ids := make([]int64, 0)
+	from := since.UnixNano() / int64(time.Millisecond)
+
+	for {
+		var page struct {
+			Values []struct {
+				WorklogID int64 `json:"worklogId"`
+			} `json:"values"`
+			Until    int64 `json:"until"`
+			LastPage bool  `json:"lastPage"`
+		}
+
+		err, body := f.get(f.apiPath("/worklog/updated"), map[string]string{"since": strconv.FormatInt(from, 10)})
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve worklogs updated since %s", since), nil
+		}
+
+		if err := json.Unmarshal(body, &page); err != nil {
+			return errors.Wrapf(err, "failed to parse worklogs updated since %s", since), nil
+		}
+
+		for _, v := range page.Values {
+			ids = append(ids, v.WorklogID)
+		}
+
+		if page.LastPage || len(page.Values) == 0 || page.Until <= from {
+			return nil, ids
+		}
+		from = page.Until
+	}
+}
+
+// listWorklogs retrieves the details of the worklogs
+func (f *JiraFinder) listWorklogs(ids []int64) (error, []Worklog) {
+	request, err := json.Marshal(map[string][]int64{"ids": ids})
+	if err != nil {
+		return errors.Wrap(err, "failed to prepare the worklogs request"), nil
+	}
+
+	err, body := f.post(f.apiPath("/worklog/list"), request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve %d worklogs", len(ids)), nil
+	}
+
+	var values []map[string]interface{}
+	if err := json.Unmarshal(body, &values); err != nil {
+		return errors.Wrap(err, "failed to parse worklogs"), nil
+	}
+
+	worklogs := make([]Worklog, 0, len(values))
+	for _, v := range values {
+		worklogs = append(worklogs, parseWorklog(v))
+	}
+
+	return nil, worklogs
+}
+
+func parseWorklog(value map[string]interface{}) Worklog {
+	started, _ := time.Parse(jiraTimeLayout, nestedString(value, "started"))
+	spent, _ := value["timeSpentSeconds"].(float64)
+
+	return Worklog{
+		ID:               nestedString(value, "id"),
+		IssueID:          nestedString(value, "issueId"),
+		Author:           parseUser(value["author"]).String(),
+		Started:          started,
+		TimeSpentSeconds: int64(spent),
+		Comment:          textFromField(value["comment"]),
+	}
+}
@@ -0,0 +1,72 @@
+package jirafinder
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestJiraFinder_GetUpdatedWorklogs(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	since := time.Unix(1597800000, 0)
+	var listed string
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/rest/api/2/worklog/updated":
+			switch req.URL.Query().Get("since") {
+			case "1597800000000":
+				fmt.Fprint(w, `{"values": [{"worklogId": 100}, {"worklogId": 101}], "since": 1597800000000, "until": 1597900000000, "lastPage": false}`)
+			case "1597900000000":
+				fmt.Fprint(w, `{"values": [{"worklogId": 102}], "since": 1597900000000, "until": 1598000000000, "lastPage": true}`)
+			default:
+				t.Errorf("unexpected since %s", req.URL.Query().Get("since"))
+			}
+		case "/rest/api/2/worklog/list":
+			a.Equal(http.MethodPost, req.Method, "wrong list method")
+			body, _ := io.ReadAll(req.Body)
+			listed = string(body)
+			fmt.Fprint(w, `[
+  {"id": "100", "issueId": "10002", "author": {"displayName": "Jane Doe"}, "started": "2020-08-19T09:00:00.000+0300", "timeSpentSeconds": 3600, "comment": "review"},
+  {"id": "101", "issueId": "10002", "author": {"name": "jdoe"}, "started": "2020-08-19T11:00:00.000+0300", "timeSpentSeconds": 1800},
+  {"id": "102", "issueId": "10003", "author": {"displayName": "Jane Doe"}, "started": "2020-08-20T09:00:00.000+0300", "timeSpentSeconds": 7200}
+]`)
+		default:
+			t.Errorf("unexpected path %s", req.URL.Path)
+		}
+	})
+
+	err, worklogs := f.GetUpdatedWorklogs(since)
+	r.NoError(err)
+	r.JSONEq(`{"ids": [100, 101, 102]}`, listed, "the ids of both pages should be listed")
+	r.Len(worklogs, 3)
+
+	started, _ := time.Parse(time.RFC3339, "2020-08-19T09:00:00+03:00")
+	r.Equal("100", worklogs[0].ID)
+	r.Equal("10002", worklogs[0].IssueID)
+	r.Equal("Jane Doe", worklogs[0].Author)
+	r.True(started.Equal(worklogs[0].Started), "wrong started %s", worklogs[0].Started)
+	r.Equal(int64(3600), worklogs[0].TimeSpentSeconds)
+	r.Equal("review", worklogs[0].Comment)
+	r.Equal("jdoe", worklogs[1].Author)
+	r.Equal("10003", worklogs[2].IssueID)
+}
+
+func TestJiraFinder_GetUpdatedWorklogsNone(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	f := newTestFinder(t, func(w http.ResponseWriter, req *http.Request) {
+		a.Equal("/rest/api/2/worklog/updated", req.URL.Path, "no worklog should be listed")
+		fmt.Fprint(w, `{"values": [], "lastPage": true}`)
+	})
+
+	err, worklogs := f.GetUpdatedWorklogs(time.Now())
+	r.NoError(err)
+	r.Empty(worklogs)
+}